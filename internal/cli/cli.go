@@ -0,0 +1,154 @@
+// Package cli implements the command-line subcommands of axiom_shift.
+// Running the binary without arguments starts the game instead (main.go).
+package cli
+
+import (
+	"axiom_shift/internal/logic"
+	"axiom_shift/internal/usecase"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const usage = `usage:
+  axiom_shift                        start the game
+  axiom_shift --tui [flags]          play in the terminal with ANSI colors (-seed, -difficulty, -granularity)
+  axiom_shift play -seed N -inputs 3,7,0,... [-json]
+                                     play a seed without a window; exit code 0 win, 1 lose, 3 unfinished
+  axiom_shift bank fill [flags]      search seeds offline and add them to the seed bank
+  axiom_shift bank info [flags]      show how many seeds are banked per config
+`
+
+// Run executes the subcommand in args (without the program name) and returns the exit code.
+// stdin is only read by the interactive modes.
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) >= 1 && args[0] == "--tui" {
+		return playTUI(ctx, args[1:], stdin, stdout, stderr)
+	}
+	if len(args) >= 1 && args[0] == "play" {
+		return play(args[1:], stdout, stderr)
+	}
+	if len(args) >= 2 && args[0] == "bank" {
+		switch args[1] {
+		case "fill":
+			return bankFill(ctx, args[2:], stdout, stderr)
+		case "info":
+			return bankInfo(args[2:], stdout, stderr)
+		}
+	}
+	fmt.Fprint(stderr, usage)
+	return 2
+}
+
+// bankFill: 指定難易度の seed を探索して bank に追加する（1 件ごとに保存するので中断しても無駄にならない）
+func bankFill(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bank fill", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("bank", usecase.UserDataPath("seedbank.json"), "seed bank file")
+	count := fs.Int("count", 20, "number of seeds the bank should hold for the config")
+	difficulty := fs.String("difficulty", "any", "target difficulty: any, easy, normal, hard or expert")
+	seed := fs.Int64("seed", 0, "master seed for the candidates (0: random)")
+	workers := fs.Int("workers", 0, "parallel workers (0: GOMAXPROCS)")
+	granularity := fs.String("granularity", "10", "input granularity: 10, 100 or continuous")
+	constraint := fs.String("constraint", "", `winning path constraint, e.g. "lose[3] and count(key == 0) >= 2"`)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	d, err := usecase.ParseDifficulty(*difficulty)
+	g, gErr := usecase.ParseInputGranularity(*granularity)
+	if err != nil || gErr != nil || *path == "" || *count < 1 {
+		fmt.Fprintln(stderr, "invalid flags: need a bank path, -count >= 1, a known -difficulty and -granularity")
+		return 2
+	}
+
+	bank, err := usecase.LoadSeedBank(*path)
+	if err != nil {
+		fmt.Fprintf(stderr, "load %s: %v\n", *path, err)
+		return 1
+	}
+	rng := logic.NewSeedManager()
+	if *seed != 0 {
+		rng = logic.NewSeedManagerWithFixedValue(*seed)
+	}
+	config, criteria := usecase.DefaultGameConfig(), usecase.CriteriaForDifficulty(d)
+	config.Granularity = g
+	if *constraint != "" {
+		c, err := usecase.ParseConstraint(*constraint)
+		if err != nil {
+			printConstraintError(stderr, *constraint, err)
+			return 2
+		}
+		if c.MaxTurn() > config.BattleMax {
+			fmt.Fprintf(stderr, "constraint refers to turn %d, but a game has %d battles\n", c.MaxTurn(), config.BattleMax)
+			return 2
+		}
+		criteria.Constraint = c
+	}
+	key := usecase.SeedBankKey(config, criteria)
+	fmt.Fprintf(stdout, "%s (input %s): %d/%d seeds banked (%s)\n", d, g, bank.Len(key), *count, key)
+	added, err := usecase.FillSeedBank(ctx, bank, config, criteria, *count, rng, usecase.SeedSearchOptions{Workers: *workers},
+		func(r usecase.SeedReport) error {
+			fmt.Fprintf(stdout, "seed %d: %s\n", r.Seed, r.Summary())
+			return bank.Save(*path)
+		})
+	fmt.Fprintf(stdout, "added %d seeds, %d banked\n", added, bank.Len(key))
+	if err != nil {
+		fmt.Fprintf(stderr, "fill: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// printConstraintError: 制約の構文エラーを、式と位置を示す ^ 付きで表示する
+func printConstraintError(w io.Writer, src string, err error) {
+	fmt.Fprintf(w, "invalid -constraint: %v\n", err)
+	var ce *usecase.ConstraintError
+	if errors.As(err, &ce) {
+		fmt.Fprintf(w, "  %s\n  %s^\n", src, strings.Repeat(" ", ce.Pos-1))
+	}
+}
+
+// bankInfo: 標準設定の難易度・入力粒度ごとの件数と、それ以外の設定のエントリ数を表示する
+func bankInfo(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bank info", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("bank", usecase.UserDataPath("seedbank.json"), "seed bank file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	bank, err := usecase.LoadSeedBank(*path)
+	if err != nil {
+		fmt.Fprintf(stderr, "load %s: %v\n", *path, err)
+		return 1
+	}
+	known := map[string]bool{}
+	for g := usecase.InputDigits; g <= usecase.InputContinuous; g++ {
+		config := usecase.DefaultGameConfig()
+		config.Granularity = g
+		for d := usecase.DifficultyAny; d <= usecase.DifficultyExpert; d++ {
+			key := usecase.SeedBankKey(config, usecase.CriteriaForDifficulty(d))
+			known[key] = true
+			// 10 キー以外は banked のものだけ表示する
+			if g == usecase.InputDigits {
+				fmt.Fprintf(stdout, "%-6s %4d\n", d, bank.Len(key))
+			} else if bank.Len(key) > 0 {
+				fmt.Fprintf(stdout, "%-6s %4d (input %s)\n", d, bank.Len(key), g)
+			}
+		}
+	}
+	var others []string
+	for key := range bank.Entries {
+		if !known[key] {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	for _, key := range others {
+		fmt.Fprintf(stdout, "other config %s %4d\n", key, bank.Len(key))
+	}
+	return 0
+}
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"axiom_shift/internal/usecase"
+)
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no subcommand", nil},
+		{"unknown command", []string{"fly"}},
+		{"unknown bank command", []string{"bank", "empty"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := Run(context.Background(), tt.args, nil, &stdout, &stderr); code != 2 {
+				t.Errorf("exit code = %d, want 2", code)
+			}
+			if !strings.Contains(stderr.String(), "usage:") {
+				t.Errorf("stderr = %q, want usage", stderr.String())
+			}
+		})
+	}
+}
+
+func TestRun_BankFill(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		args     []string
+		wantCode int
+		wantLen  int // 標準設定・Any の件数
+	}{
+		{"fills", context.Background(), []string{"-count", "2", "-seed", "1"}, 0, 2},
+		{"bad difficulty", context.Background(), []string{"-difficulty", "insane"}, 2, 0},
+		{"bad count", context.Background(), []string{"-count", "0"}, 2, 0},
+		{"bad granularity", context.Background(), []string{"-granularity", "1000"}, 2, 0},
+		{"bad constraint", context.Background(), []string{"-constraint", "lose[3] and"}, 2, 0},
+		{"constraint after the last battle", context.Background(), []string{"-constraint", "win[11]"}, 2, 0},
+		{"constrained", context.Background(), []string{"-count", "1", "-seed", "1", "-constraint", "lose[3]"}, 0, 0},
+		{"other granularity", context.Background(), []string{"-count", "1", "-seed", "1", "-granularity", "continuous"}, 0, 0},
+		{"unknown flag", context.Background(), []string{"-verbose"}, 2, 0},
+		{"cancelled", cancelled, []string{"-count", "1", "-seed", "1"}, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".json")
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"bank", "fill", "-bank", path}, tt.args...)
+			if code := Run(tt.ctx, args, nil, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d (stderr %q)", code, tt.wantCode, stderr.String())
+			}
+			bank, err := usecase.LoadSeedBank(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			key := usecase.SeedBankKey(usecase.DefaultGameConfig(), usecase.DefaultSeedCriteria())
+			if bank.Len(key) != tt.wantLen {
+				t.Errorf("banked = %d, want %d", bank.Len(key), tt.wantLen)
+			}
+		})
+	}
+	t.Run("constraint error position", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		Run(context.Background(), []string{"bank", "fill", "-bank", filepath.Join(dir, "x.json"), "-constraint", "wins = 2"}, nil, &stdout, &stderr)
+		if want := "constraint:6: unexpected character \"=\"\n  wins = 2\n       ^\n"; !strings.HasSuffix(stderr.String(), want) {
+			t.Errorf("stderr = %q, want suffix %q", stderr.String(), want)
+		}
+	})
+	t.Run("corrupt bank", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := Run(context.Background(), []string{"bank", "fill", "-bank", corrupt}, nil, &stdout, &stderr); code != 1 {
+			t.Errorf("exit code = %d, want 1", code)
+		}
+	})
+}
+
+func TestRun_BankInfo(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bank.json")
+	bank := usecase.NewSeedBank()
+	bank.Add(usecase.SeedBankKey(usecase.DefaultGameConfig(), usecase.CriteriaForDifficulty(usecase.DifficultyHard)), usecase.SeedReport{Seed: 1})
+	bank.Add("0123", usecase.SeedReport{Seed: 2})
+	continuous := usecase.DefaultGameConfig()
+	continuous.Granularity = usecase.InputContinuous
+	bank.Add(usecase.SeedBankKey(continuous, usecase.CriteriaForDifficulty(usecase.DifficultyEasy)), usecase.SeedReport{Seed: 3})
+	if err := bank.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     []string
+	}{
+		{"counts", []string{"-bank", path}, 0, []string{"Hard      1", "Easy      0", "Easy      1 (input continuous)", "other config 0123    1"}},
+		{"corrupt", []string{"-bank", corrupt}, 1, nil},
+		{"unknown flag", []string{"-x"}, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := Run(context.Background(), append([]string{"bank", "info"}, tt.args...), nil, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(stdout.String(), w) {
+					t.Errorf("stdout %q does not contain %q", stdout.String(), w)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_TUI(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	seed := []string{"-seed", "5823616476339260602"}
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		args     []string
+		stdin    io.Reader
+		wantCode int
+		want     []string
+	}{
+		{"plays keys from stdin", context.Background(), seed, strings.NewReader("3\r7\rq"), 0,
+			[]string{"Battle 1: Input=3", "Battle 2: Input=7", "Seed 5823616476339260602 (", "/2 battles won"}},
+		{"typed entry", context.Background(), append(seed, "-granularity", "continuous"), strings.NewReader("0.25\r\r"), 0,
+			[]string{"Battle 1: Input=0.25"}},
+		{"share code", context.Background(), []string{"-seed", "041G-M000-A38T-8N4M-A16B-NNKF"}, strings.NewReader("3\rq"), 0,
+			[]string{"Battle 1: Input=3", "Seed 5823616476339260602 (041G-M000-A38T-8N4M-A16B-NNKF)"}},
+		{"searches a seed", context.Background(), []string{"-difficulty", "any"}, strings.NewReader(""), 0,
+			[]string{"Searching for a new random seed (Any)", "0/0 battles won"}},
+		{"empty seed searches", context.Background(), []string{"-seed", ""}, strings.NewReader(""), 0,
+			[]string{"Searching for a new random seed (Any)"}},
+		{"search cancelled", cancelled, nil, strings.NewReader(""), 1, nil},
+		{"read error", context.Background(), seed, iotest.ErrReader(errors.New("broken")), 1, nil},
+		{"bad seed", context.Background(), []string{"-seed", "not-a-code"}, nil, 2, nil},
+		{"bad difficulty", context.Background(), []string{"-difficulty", "insane"}, nil, 2, nil},
+		{"bad granularity", context.Background(), []string{"-granularity", "3"}, nil, 2, nil},
+		{"unknown flag", context.Background(), []string{"-x"}, nil, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := Run(tt.ctx, append([]string{"--tui"}, tt.args...), tt.stdin, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d (stderr %q)", code, tt.wantCode, stderr.String())
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(stdout.String(), w) {
+					t.Errorf("stdout %q does not contain %q", stdout.String(), w)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_Play(t *testing.T) {
+	seed := []string{"-seed", "5823616476339260602"}
+	// FallbackSeed で勝つ入力と負ける入力
+	winning, losing := "3,7,0,1,2,3,4,5,6,9", "0,0,0,0,0,0,0,0,0,0"
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     []string
+	}{
+		{"win", append(seed, "-inputs", winning), 0, []string{"Seed 5823616476339260602, 10 battles, input 10", "Battle 10: Input=", "GAME WIN ("}},
+		{"lose", append(seed, "-inputs", losing), 1, []string{"GAME LOSE ("}},
+		{"unfinished", append(seed, "-inputs", "3, 7"), 3, []string{"Battle 1: Input=3 ", "Battle 2: Input=7 ", "UNFINISHED after 2/10 battles"}},
+		{"no inputs", seed, 3, []string{"UNFINISHED after 0/10 battles (0 won)"}},
+		{"json", append(seed, "-inputs", winning, "-json"), 0, []string{`"seed": 5823616476339260602`, `"battle_max": 10`, `"outcome": "win"`, `"granularity": "10"`, `"battle": 10`}},
+		{"json without inputs", append(seed, "-json"), 3, []string{`"turns": []`, `"outcome": "unfinished"`}},
+		{"percent", append(seed, "-granularity", "100", "-inputs", "42,0.5"), 3, []string{"input 100", "Battle 1: Input=42 ", "Battle 2: Input=50 "}},
+		{"continuous", append(seed, "-granularity", "continuous", "-inputs", "0.375"), 3, []string{"Battle 1: Input=0.375 "}},
+		{"share code", []string{"-seed", "041G-M000-A38T-8N4M-A16B-NNKF", "-inputs", "3"}, 3, []string{"Seed 5823616476339260602, 10 battles"}},
+		{"missing seed", []string{"-inputs", "3"}, 2, nil},
+		{"bad seed", []string{"-seed", "not-a-code"}, 2, nil},
+		{"bad granularity", append(seed, "-granularity", "7"), 2, nil},
+		{"bad input", append(seed, "-inputs", "3,10"), 2, nil},
+		{"too many inputs", append(seed, "-inputs", "1,1,1,1,1,1,1,1,1,1,1"), 2, nil},
+		{"unknown flag", []string{"-x"}, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := Run(context.Background(), append([]string{"play"}, tt.args...), nil, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d (stderr %q)", code, tt.wantCode, stderr.String())
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(stdout.String(), w) {
+					t.Errorf("stdout %q does not contain %q", stdout.String(), w)
+				}
+			}
+		})
+	}
+}
+
+// play の結果は同じ seed・入力の Replay と一致する
+func TestRun_PlayMatchesReplay(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	Run(context.Background(), []string{"play", "-seed", "5823616476339260602", "-inputs", "3,7,0,1,2,3,4,5,6,9", "-json"}, nil, &stdout, &stderr)
+	var got struct {
+		Turns []usecase.TurnRecord `json:"turns"`
+		Win   bool                 `json:"win"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	config := usecase.DefaultGameConfig()
+	player, enemy := config.NewCombatants()
+	steps := usecase.Replay(usecase.FallbackSeed, player, enemy, []float64{3.0 / 9, 7.0 / 9, 0, 1.0 / 9, 2.0 / 9, 3.0 / 9, 4.0 / 9, 5.0 / 9, 6.0 / 9, 1})
+	if len(got.Turns) != len(steps) {
+		t.Fatalf("%d turns, want %d", len(got.Turns), len(steps))
+	}
+	for i, step := range steps {
+		if got.Turns[i].Result != step.Result || got.Turns[i].Win != step.Win {
+			t.Errorf("turn %d = %+v, replay %+v", i+1, got.Turns[i], step)
+		}
+	}
+	if got.Win != steps[len(steps)-1].Win {
+		t.Errorf("Win = %v", got.Win)
+	}
+}
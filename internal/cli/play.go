@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"axiom_shift/internal/logic"
+	"axiom_shift/internal/usecase"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// play の終了コード（2 は他のコマンドと同じく引数の誤り）
+const (
+	exitWin        = 0
+	exitLose       = 1
+	exitUnfinished = 3
+)
+
+// playResult: -json で出力する内容
+type playResult struct {
+	usecase.SessionResult
+	Granularity string `json:"granularity"`
+	Wins        int    `json:"wins"`
+	Outcome     string `json:"outcome"` // win, lose または unfinished
+}
+
+// play: seed と入力列でゲームを画面なしに進め、各バトルと勝敗を表示する
+// 終了コードは勝ち 0・負け 1・入力が足りず最終戦まで終わらなかった場合 3
+func play(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("play", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	seed := fs.String("seed", "", "seed or share code to play")
+	inputs := fs.String("inputs", "", "comma-separated inputs: levels (0-9 or 0-99) or numbers from 0 to 1")
+	granularity := fs.String("granularity", "10", "input granularity: 10, 100 or continuous")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	g, err := usecase.ParseInputGranularity(*granularity)
+	if err != nil || strings.TrimSpace(*seed) == "" {
+		fmt.Fprintln(stderr, "invalid flags: need a -seed and a known -granularity")
+		return 2
+	}
+	config := usecase.DefaultGameConfig()
+	size := config.Size()
+	code, err := logic.ParseSeedInput(*seed, logic.ShareCode{Size: size, BattleMax: config.BattleMax, Generator: logic.GeneratorUniform})
+	if err == nil {
+		err = code.Validate(size)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid -seed: %v\n", err)
+		return 2
+	}
+	xs, err := parseInputs(*inputs, g)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid -inputs: %v\n", err)
+		return 2
+	}
+	if len(xs) > code.BattleMax {
+		fmt.Fprintf(stderr, "invalid -inputs: %d inputs for %d battles\n", len(xs), code.BattleMax)
+		return 2
+	}
+
+	player, enemy := config.NewCombatants()
+	session := usecase.NewGameSession(code.Seed, code.BattleMax, player, enemy, g)
+	for i, x := range xs {
+		err := session.SubmitInput(x)
+		if err == nil {
+			_, err = session.Confirm()
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "battle %d: %v\n", i+1, err)
+			return 2
+		}
+	}
+
+	r := playResult{SessionResult: session.Result(), Granularity: g.String(), Outcome: "unfinished"}
+	if r.Turns == nil {
+		r.Turns = []usecase.TurnRecord{} // JSON では null ではなく空配列
+	}
+	for _, t := range r.Turns {
+		if t.Win {
+			r.Wins++
+		}
+	}
+	exit := exitUnfinished
+	if r.Over {
+		r.Outcome, exit = "lose", exitLose
+		if r.Win {
+			r.Outcome, exit = "win", exitWin
+		}
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			fmt.Fprintf(stderr, "json: %v\n", err)
+			return 2
+		}
+		return exit
+	}
+	fmt.Fprintf(stdout, "Seed %d, %d battles, input %s\n", r.Seed, r.BattleMax, g)
+	for _, t := range r.Turns {
+		fmt.Fprintf(stdout, "Battle %d: Input=%s Result=%.2f Win/Lose=%s\n", t.Battle, g.FormatInput(t.Input), t.Result, winLose(t.Win))
+	}
+	fmt.Fprintln(stdout, "---")
+	switch r.Outcome {
+	case "unfinished":
+		fmt.Fprintf(stdout, "UNFINISHED after %d/%d battles (%d won)\n", len(r.Turns), r.BattleMax, r.Wins)
+	default:
+		fmt.Fprintf(stdout, "GAME %s (%d/%d battles won)\n", strings.ToUpper(r.Outcome), r.Wins, r.BattleMax)
+	}
+	return exit
+}
+
+// parseInputs: カンマ区切りの入力を粒度に合わせて解釈する。空なら入力なし
+func parseInputs(s string, g usecase.InputGranularity) ([]float64, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var xs []float64
+	for i, field := range strings.Split(s, ",") {
+		x, err := g.ParseInput(field)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", i+1, err)
+		}
+		xs = append(xs, x)
+	}
+	return xs, nil
+}
+
+// winLose: バトルログと同じ英語表記の勝敗
+func winLose(win bool) string {
+	if win {
+		return "WIN"
+	}
+	return "LOSE"
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"axiom_shift/internal/logic"
+	"axiom_shift/internal/tui"
+	"axiom_shift/internal/usecase"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+const (
+	hideCursor = "\x1b[?25l"
+	showCursor = "\x1b[?25h"
+)
+
+// playTUI: 端末上で 1 つの seed を遊ぶ（--tui）。-seed を省くと選んだ難易度の seed を探す
+// stdin が端末なら raw モードにして 1 キーずつ読む
+func playTUI(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("--tui", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	seed := fs.String("seed", "", "seed or share code to play (empty: search a new random seed)")
+	difficulty := fs.String("difficulty", "any", "difficulty of the searched seed: any, easy, normal, hard or expert")
+	granularity := fs.String("granularity", "10", "input granularity: 10, 100 or continuous")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	d, err := usecase.ParseDifficulty(*difficulty)
+	g, gErr := usecase.ParseInputGranularity(*granularity)
+	if err != nil || gErr != nil {
+		fmt.Fprintln(stderr, "invalid flags: need a known -difficulty and -granularity")
+		return 2
+	}
+
+	config := usecase.DefaultGameConfig()
+	config.Granularity = g
+	size := config.Size()
+	var report usecase.SeedReport
+	codeDifficulty := int(usecase.DifficultyAny)
+	if *seed != "" {
+		code, err := logic.ParseSeedInput(*seed, logic.ShareCode{Size: size, BattleMax: config.BattleMax, Generator: logic.GeneratorUniform})
+		if err == nil {
+			err = code.Validate(size)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid -seed: %v\n", err)
+			return 2
+		}
+		report.Seed, config.BattleMax, codeDifficulty = code.Seed, code.BattleMax, code.Difficulty
+	} else {
+		fmt.Fprintf(stdout, "Searching for a new random seed (%s)...\n", d)
+		player, enemy := config.NewCombatants()
+		report, err = usecase.FindSeed(ctx, config.BattleMax, player, enemy, logic.NewSeedManager(), usecase.CriteriaForDifficulty(d), usecase.SeedSearchOptions{Granularity: g})
+		if err != nil {
+			fmt.Fprintf(stderr, "seed search: %v\n", err)
+			return 1
+		}
+		codeDifficulty = int(report.Difficulty())
+	}
+	code, err := logic.ShareCode{
+		Seed:       report.Seed,
+		Size:       size,
+		BattleMax:  config.BattleMax,
+		Difficulty: codeDifficulty,
+		Generator:  logic.GeneratorUniform,
+	}.Encode()
+	if err != nil {
+		fmt.Fprintf(stderr, "share code: %v\n", err)
+		return 1
+	}
+
+	t := tui.New(stdout)
+	player, enemy := config.NewCombatants()
+	if err := t.Engine().Start(report.Seed, code, config.BattleMax, player, enemy, g); err != nil {
+		fmt.Fprintf(stderr, "start: %v\n", err)
+		return 1
+	}
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			fmt.Fprintf(stderr, "raw terminal: %v\n", err)
+			return 1
+		}
+		defer term.Restore(int(f.Fd()), state)
+	}
+	fmt.Fprint(stdout, hideCursor)
+	err = t.Run(ctx, stdin)
+	vm := t.Engine().View()
+	fmt.Fprintf(stdout, "%s\r\nSeed %d (%s): %d/%d battles won\r\n", showCursor, vm.Seed, vm.ShareCode, vm.Wins, vm.Battle)
+	if err != nil {
+		fmt.Fprintf(stderr, "tui: %v\r\n", err)
+		return 1
+	}
+	return 0
+}
@@ -68,6 +68,17 @@ func (e *Enemy) Grow(input float64, rule *Matrix) {
 	e.MatrixState.Data[maxI][maxJ] += 0.5 * e.GrowthRate
 }
 
+// Clone returns a deep copy of the enemy, including its initial state, so that
+// independent simulations can run concurrently.
+func (e *Enemy) Clone() *Enemy {
+	return &Enemy{
+		Name:         e.Name,
+		initialState: *e.initialState.Copy(),
+		MatrixState:  e.MatrixState.Copy(),
+		GrowthRate:   e.GrowthRate,
+	}
+}
+
 func (e *Enemy) GetMatrix() *Matrix {
 	return e.MatrixState
 }
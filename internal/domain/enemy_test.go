@@ -146,3 +146,37 @@ func TestEnemy_Reset(t *testing.T) {
 		})
 	}
 }
+
+func TestEnemy_Clone(t *testing.T) {
+	tests := []struct {
+		name string
+		data [][]float64
+	}{
+		{"normal", [][]float64{{1, 2}, {3, 4}}},
+		{"nil matrix", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m *Matrix
+			if tt.data != nil {
+				m = NewMatrix(tt.data)
+			}
+			e := NewEnemy("E", m, 0.5)
+			c := e.Clone()
+			if c.Name != e.Name || c.GrowthRate != e.GrowthRate || !equal(c.MatrixState, e.MatrixState) {
+				t.Fatalf("Clone mismatch: got %+v, want %+v", c, e)
+			}
+			if c.MatrixState == nil {
+				return
+			}
+			c.Grow(0, NewMatrix([][]float64{{1, 0}, {0, 1}}))
+			if equal(c.MatrixState, e.MatrixState) {
+				t.Error("Clone shares MatrixState with original")
+			}
+			c.Reset()
+			if c.MatrixState.Data[0][0] != tt.data[0][0] {
+				t.Errorf("Clone Reset: got %v, want %v", c.MatrixState.Data[0][0], tt.data[0][0])
+			}
+		})
+	}
+}
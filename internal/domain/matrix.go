@@ -112,6 +112,15 @@ func (m *Matrix) Copy() *Matrix {
 	return &Matrix{Data: newData, Rows: m.Rows, Cols: m.Cols}
 }
 
+// CopyRows returns a deep copy of matrix data, so callers can keep a snapshot of it.
+func CopyRows(data [][]float64) [][]float64 {
+	rows := make([][]float64, len(data))
+	for i, row := range data {
+		rows[i] = append([]float64(nil), row...)
+	}
+	return rows
+}
+
 // sqrt is a helper for square root (for normalization)
 func sqrt(x float64) float64 {
 	if x == 0 {
@@ -177,6 +177,40 @@ func TestMatrixCopy_GuardClauses(t *testing.T) {
 	}
 }
 
+func TestCopyRows(t *testing.T) {
+	tests := []struct {
+		name string
+		data [][]float64
+	}{
+		{"nil", nil},
+		{"empty", [][]float64{}},
+		{"square", [][]float64{{1, 0}, {0, 2}}},
+		{"ragged", [][]float64{{1}, {2, 3}, {}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := CopyRows(tt.data)
+			if len(rows) != len(tt.data) {
+				t.Fatalf("len = %d, want %d", len(rows), len(tt.data))
+			}
+			for i, row := range rows {
+				if len(row) != len(tt.data[i]) {
+					t.Fatalf("row %d = %v, want %v", i, row, tt.data[i])
+				}
+				for j := range row {
+					if row[j] != tt.data[i][j] {
+						t.Errorf("row %d = %v, want %v", i, row, tt.data[i])
+					}
+					row[j] = 99
+					if tt.data[i][j] == 99 {
+						t.Error("CopyRows: not deep copy")
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestMatrix_equal(t *testing.T) {
 	tests := []struct {
 		name string
@@ -53,6 +53,16 @@ func (p *Player) UpdateMatrix(input float64) {
 	}
 }
 
+// Clone returns a deep copy of the player, including its initial state, so that
+// independent simulations can run concurrently.
+func (p *Player) Clone() *Player {
+	return &Player{
+		initialState: *p.initialState.Copy(),
+		MatrixState:  p.MatrixState.Copy(),
+		GrowthRate:   p.GrowthRate,
+	}
+}
+
 func (p *Player) GetMatrix() *Matrix {
 	return p.MatrixState
 }
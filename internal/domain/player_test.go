@@ -139,3 +139,38 @@ func TestPlayer_NewPlayer(t *testing.T) {
 		})
 	}
 }
+
+func TestPlayer_Clone(t *testing.T) {
+	tests := []struct {
+		name string
+		data [][]float64
+	}{
+		{"normal", [][]float64{{1, 2}, {3, 4}}},
+		{"nil matrix", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m *Matrix
+			if tt.data != nil {
+				m = NewMatrix(tt.data)
+			}
+			p := NewPlayer(m, 0.5)
+			c := p.Clone()
+			if c.GrowthRate != p.GrowthRate || !equal(c.MatrixState, p.MatrixState) {
+				t.Fatalf("Clone mismatch: got %+v, want %+v", c, p)
+			}
+			if c.MatrixState == nil {
+				return
+			}
+			c.UpdateMatrix(0)
+			if equal(c.MatrixState, p.MatrixState) {
+				t.Error("Clone shares MatrixState with original")
+			}
+			c.Reset()
+			p.MatrixState.Data[0][0] = 99
+			if c.MatrixState.Data[0][0] != tt.data[0][0] {
+				t.Errorf("Clone Reset: got %v, want %v", c.MatrixState.Data[0][0], tt.data[0][0])
+			}
+		})
+	}
+}
@@ -4,6 +4,12 @@ import (
 	"math/rand"
 )
 
+// Float64Source is the minimal random source needed to generate a rule matrix.
+// logic.RandomSource satisfies it.
+type Float64Source interface {
+	Float64() float64
+}
+
 // RuleMatrix represents the matrix of rules that will be used in battles.
 type RuleMatrix struct {
 	*Matrix
@@ -11,12 +17,16 @@ type RuleMatrix struct {
 
 // NewRuleMatrix generates a new RuleMatrix based on a given seed.
 func NewRuleMatrix(seed int64, size int) *RuleMatrix {
-	r := rand.New(rand.NewSource(seed))
+	return NewRuleMatrixFromSource(rand.New(rand.NewSource(seed)), size)
+}
+
+// NewRuleMatrixFromSource generates a new RuleMatrix drawing values from src.
+func NewRuleMatrixFromSource(src Float64Source, size int) *RuleMatrix {
 	data := make([][]float64, size)
 	for i := range data {
 		data[i] = make([]float64, size)
 		for j := range data[i] {
-			data[i][j] = r.Float64()*2 - 1 // -1〜+1の範囲でランダム
+			data[i][j] = src.Float64()*2 - 1 // -1〜+1の範囲でランダム
 		}
 	}
 	matrix := NewMatrix(data)
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"math/rand"
 	"testing"
 )
 
@@ -31,3 +32,24 @@ func TestNewRuleMatrix(t *testing.T) {
 		})
 	}
 }
+
+func TestNewRuleMatrixFromSource(t *testing.T) {
+	tests := []struct {
+		name string
+		seed int64
+		size int
+	}{
+		{"size 0", 1, 0},
+		{"size 2", 42, 2},
+		{"size 3", 7, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewRuleMatrixFromSource(rand.New(rand.NewSource(tt.seed)), tt.size)
+			want := NewRuleMatrix(tt.seed, tt.size)
+			if !equal(got.Matrix, want.Matrix) {
+				t.Errorf("NewRuleMatrixFromSource: got %v, want %v", got.Matrix.Data, want.Matrix.Data)
+			}
+		})
+	}
+}
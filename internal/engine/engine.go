@@ -0,0 +1,190 @@
+// Package engine is the renderer-agnostic core of the game: it runs the phases, the session
+// and the battle log from semantic input actions and describes each frame as a ViewModel.
+// Front ends (Ebiten, terminal, recorders) only map keys to actions and draw the view.
+package engine
+
+import (
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/usecase"
+	"errors"
+	"fmt"
+)
+
+// ErrNoGame is returned by Retry before any game has started.
+var ErrNoGame = errors.New("no game started")
+
+// EntryMaxLen is the longest input a player can type for the finer granularities ("0." and ten decimals).
+const EntryMaxLen = 12
+
+// Engine holds the state of one game front end between frames.
+type Engine struct {
+	phase     *usecase.PhaseMachine
+	input     usecase.InputSource
+	session   *usecase.GameSession // Start までは nil
+	loop      *usecase.GameLoop
+	frame     *frameInput // loop に渡す現在フレームの操作
+	onTurn    []func(usecase.TurnRecord)
+	log       []string
+	entry     string // 10 キー以外の粒度で入力中の文字列
+	message   string // 入力のエラー表示
+	shareCode string
+}
+
+// New returns an engine in the menu phase that reads in-game actions from input.
+func New(input usecase.InputSource) *Engine {
+	if input == nil {
+		panic("Invalid parameters: input must not be nil")
+	}
+	e := &Engine{
+		phase: usecase.NewPhaseMachine(usecase.PhaseMenu, usecase.GameTransitions()),
+		input: input,
+		frame: &frameInput{},
+	}
+	e.phase.OnEnter(usecase.PhaseInput, func(from usecase.Phase) {
+		switch from {
+		case usecase.PhaseConfirm: // 再入力では打ちかけの値を残す
+		case usecase.PhaseBattle:
+			e.entry = ""
+			e.message = ""
+		default: // ゲーム開始・リトライ
+			e.entry = ""
+			e.message = ""
+			e.log = nil
+		}
+	})
+	return e
+}
+
+// Phases returns the phase machine so front ends can move through menus and register hooks.
+func (e *Engine) Phases() *usecase.PhaseMachine {
+	return e.phase
+}
+
+// Start begins a game on seed and moves to the input phase. shareCode is shown in the view.
+// player and enemy are owned by the session from now on.
+func (e *Engine) Start(seed int64, shareCode string, battleMax int, player *domain.Player, enemy *domain.Enemy, granularity usecase.InputGranularity) error {
+	session := usecase.NewGameSession(seed, battleMax, player, enemy, granularity)
+	loop := usecase.NewGameLoop(session, e.phase, e.frame)
+	loop.OnTurn(e.logTurn)
+	for _, fn := range e.onTurn {
+		loop.OnTurn(fn)
+	}
+	e.session, e.loop, e.shareCode = session, loop, shareCode
+	return e.phase.Transition(usecase.PhaseInput)
+}
+
+// Retry restarts the current game on the same seed.
+func (e *Engine) Retry() error {
+	if e.session == nil {
+		return ErrNoGame
+	}
+	e.session.Retry()
+	return e.phase.Transition(usecase.PhaseInput)
+}
+
+// Session returns the current game, or nil before Start.
+func (e *Engine) Session() *usecase.GameSession {
+	return e.session
+}
+
+// OnTurn registers fn to run after every battle of this and later games, after the battle is logged.
+func (e *Engine) OnTurn(fn func(usecase.TurnRecord)) {
+	e.onTurn = append(e.onTurn, fn)
+	if e.loop != nil {
+		e.loop.OnTurn(fn)
+	}
+}
+
+// Step processes one frame of input. Outside the in-game phases it only consumes the frame.
+// With a finer granularity the input phase reads the typed entry: Confirm submits it and Cancel deletes a character.
+func (e *Engine) Step() error {
+	actions := e.input.Actions()
+	if e.session == nil {
+		return nil
+	}
+	if e.phase.Current() == usecase.PhaseInput && e.session.Granularity() != usecase.InputDigits {
+		for _, a := range actions {
+			switch a.Kind {
+			case usecase.ActionConfirm:
+				e.submitEntry()
+				return nil
+			case usecase.ActionCancel:
+				if len(e.entry) > 0 {
+					e.entry = e.entry[:len(e.entry)-1]
+				}
+				return nil
+			}
+		}
+		return nil
+	}
+	e.frame.actions = actions
+	return e.loop.Step()
+}
+
+// TypeEntry appends typed text to the input entry. Only digits and '.' are kept, up to EntryMaxLen.
+func (e *Engine) TypeEntry(s string) {
+	for _, r := range s {
+		if (r >= '0' && r <= '9' || r == '.') && len(e.entry) < EntryMaxLen {
+			e.entry += string(r)
+		}
+	}
+}
+
+// submitEntry: 入力中の文字列を解釈して確認フェーズへ。解釈できなければメッセージを出す
+func (e *Engine) submitEntry() {
+	x, err := e.session.Granularity().ParseInput(e.entry)
+	if err == nil {
+		err = e.loop.Submit(x)
+	}
+	if err != nil {
+		e.message = err.Error()
+		return
+	}
+	e.message = ""
+}
+
+// AddLog appends a line to the battle log shown in the view.
+func (e *Engine) AddLog(line string) {
+	e.log = append(e.log, line)
+}
+
+// ClearLog empties the battle log.
+func (e *Engine) ClearLog() {
+	e.log = nil
+}
+
+// logTurn: バトル 1 回ごとのログ。最終戦なら勝敗も
+func (e *Engine) logTurn(turn usecase.TurnRecord) {
+	e.AddLog(fmt.Sprintf("Battle %d: Input=%s Result=%s Win/Lose=%s", turn.Battle, e.session.Granularity().FormatInput(turn.Input), FormatFloat(turn.Result), WinLose(turn.Win)))
+	if !e.session.Over() {
+		return
+	}
+	e.AddLog("---")
+	if e.session.Result().Win {
+		e.AddLog("[GAME WIN] Congratulations!")
+	} else {
+		e.AddLog("[GAME LOSE] Try again!")
+	}
+}
+
+// frameInput: Step で読んだ 1 フレーム分の操作を GameLoop に渡す
+type frameInput struct {
+	actions []usecase.Action
+}
+
+func (f *frameInput) Actions() []usecase.Action {
+	return f.actions
+}
+
+// FormatFloat formats every number shown to the player the same way.
+func FormatFloat(f float64) string {
+	return fmt.Sprintf("%.2f", f)
+}
+
+// WinLose returns the English label of a battle outcome.
+func WinLose(win bool) string {
+	if win {
+		return "WIN"
+	}
+	return "LOSE"
+}
@@ -0,0 +1,312 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"axiom_shift/internal/usecase"
+)
+
+// testConfig: 2x2 行列・3 戦の小さなゲーム
+func testConfig() usecase.GameConfig {
+	return usecase.GameConfig{
+		BattleMax:    3,
+		PlayerMatrix: [][]float64{{2, 0}, {0, 2}},
+		PlayerGrowth: 0.5,
+		EnemyName:    "E",
+		EnemyMatrix:  [][]float64{{0, 2}, {2, 0}},
+		EnemyGrowth:  0.5,
+	}
+}
+
+// startEngine: src の操作で動くエンジンで seed 13 のゲームを始める
+func startEngine(t *testing.T, src usecase.InputSource, granularity usecase.InputGranularity) *Engine {
+	t.Helper()
+	e := New(src)
+	config := testConfig()
+	player, enemy := config.NewCombatants()
+	if err := e.Start(13, "CODE", config.BattleMax, player, enemy, granularity); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// turn: 1 戦分の操作（数字・決定・バトル処理のフレーム）
+func turn(digit int) [][]usecase.Action {
+	return [][]usecase.Action{{usecase.DigitAction(digit)}, {usecase.ConfirmAction}, nil}
+}
+
+func frames(turns ...[][]usecase.Action) *usecase.ScriptedInput {
+	var all [][]usecase.Action
+	for _, t := range turns {
+		all = append(all, t...)
+	}
+	return usecase.NewScriptedInput(all...)
+}
+
+func run(t *testing.T, e *Engine, src *usecase.ScriptedInput) {
+	t.Helper()
+	for !src.Done() {
+		if err := e.Step(); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+}
+
+func TestEngine_Play(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        *usecase.ScriptedInput
+		wantInputs []float64
+		wantView   ViewModel // Phase・Battle・Wins・Over・Win・Prompt を比べる
+		wantLog    int       // ログの行数
+	}{
+		{"full game", frames(turn(3), turn(2), turn(5)), []float64{3.0 / 9, 2.0 / 9, 5.0 / 9},
+			ViewModel{Phase: usecase.PhaseEnd, Battle: 3, Wins: 1, Over: true, Win: true, Prompt: "GAME WIN! R: retry same rule"}, 5},
+		{"one battle", frames(turn(3)), []float64{3.0 / 9},
+			ViewModel{Phase: usecase.PhaseInput, Battle: 1, Prompt: "Press 0-9 to input"}, 1},
+		{"re-input before the battle", frames([][]usecase.Action{{usecase.DigitAction(1)}, {usecase.CancelAction}}, turn(3)), []float64{3.0 / 9},
+			ViewModel{Phase: usecase.PhaseInput, Battle: 1, Prompt: "Press 0-9 to input"}, 1},
+		{"retry clears the log", frames(turn(0), turn(0), turn(0), [][]usecase.Action{{usecase.RetryAction}}, turn(1)), []float64{1.0 / 9},
+			ViewModel{Phase: usecase.PhaseInput, Battle: 1, Prompt: "Press 0-9 to input"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := startEngine(t, tt.src, usecase.InputDigits)
+			run(t, e, tt.src)
+			if got := e.Session().Inputs(); !reflect.DeepEqual(got, tt.wantInputs) {
+				t.Errorf("Inputs = %v, want %v", got, tt.wantInputs)
+			}
+			v := e.View()
+			got := ViewModel{Phase: v.Phase, Battle: v.Battle, Wins: v.Wins, Over: v.Over, Win: v.Win, Prompt: v.Prompt}
+			if !reflect.DeepEqual(got, tt.wantView) {
+				t.Errorf("view %+v, want %+v", got, tt.wantView)
+			}
+			if len(v.Log) != tt.wantLog || !strings.HasPrefix(v.Log[0], "Battle 1: Input=") {
+				t.Errorf("log %q, want %d lines", v.Log, tt.wantLog)
+			}
+		})
+	}
+}
+
+// entryFrame: 打った文字（フレームの前に TypeEntry する）と、そのフレームの操作
+type entryFrame struct {
+	typed  string
+	action usecase.Action
+}
+
+func TestEngine_TypedEntry(t *testing.T) {
+	confirm, cancel := usecase.ConfirmAction, usecase.CancelAction
+	tests := []struct {
+		name        string
+		granularity usecase.InputGranularity
+		frames      []entryFrame
+		wantPhase   usecase.Phase
+		wantEntry   string
+		wantPending float64 // 確認フェーズのときの入力
+		wantBattle  int
+		wantMessage bool
+	}{
+		{"percent", usecase.InputPercent, []entryFrame{{"42", confirm}}, usecase.PhaseConfirm, "42", 42.0 / 99, 0, false},
+		{"continuous", usecase.InputContinuous, []entryFrame{{"0.4375", confirm}}, usecase.PhaseConfirm, "0.4375", 0.4375, 0, false},
+		{"filters characters", usecase.InputContinuous, []entryFrame{{"0x.5", confirm}}, usecase.PhaseConfirm, "0.5", 0.5, 0, false},
+		{"cancel deletes a character", usecase.InputContinuous, []entryFrame{{"0.55", cancel}, {"", confirm}}, usecase.PhaseConfirm, "0.5", 0.5, 0, false},
+		{"typed across frames", usecase.InputContinuous, []entryFrame{{"0.", usecase.DigitAction(3)}, {"7", confirm}}, usecase.PhaseConfirm, "0.7", 0.7, 0, false},
+		{"invalid entry", usecase.InputContinuous, []entryFrame{{"..", confirm}}, usecase.PhaseInput, "..", 0, 0, true},
+		{"digits ignored", usecase.InputContinuous, []entryFrame{{"", usecase.DigitAction(3)}}, usecase.PhaseInput, "", 0, 0, false},
+		{"cancel on empty entry", usecase.InputContinuous, []entryFrame{{"", cancel}}, usecase.PhaseInput, "", 0, 0, false},
+		{"entry limit", usecase.InputContinuous, []entryFrame{{"0.12345678901234", confirm}}, usecase.PhaseConfirm, "0.1234567890", 0.1234567890, 0, false},
+		// 確認で取り消しても打った値は残り、そのまま決め直せる
+		{"entry kept on re-input", usecase.InputContinuous, []entryFrame{{"0.3", confirm}, {"", cancel}, {"", confirm}}, usecase.PhaseConfirm, "0.3", 0.3, 0, false},
+		// バトル後の入力欄は空になる（3 フレーム目がバトル処理。連続入力では数字キーは無視される）
+		{"entry cleared after a battle", usecase.InputContinuous, []entryFrame{{"0.3", confirm}, {"", confirm}, {"", usecase.DigitAction(0)}}, usecase.PhaseInput, "", 0, 1, false},
+		{"message cleared by a valid entry", usecase.InputContinuous, []entryFrame{{"2", confirm}, {"", cancel}, {"1", confirm}}, usecase.PhaseConfirm, "1", 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions := make([]usecase.Action, len(tt.frames))
+			for i, f := range tt.frames {
+				actions[i] = f.action
+			}
+			src := usecase.ScriptActions(actions...)
+			e := startEngine(t, src, tt.granularity)
+			for _, f := range tt.frames {
+				e.TypeEntry(f.typed)
+				if err := e.Step(); err != nil {
+					t.Fatalf("Step: %v", err)
+				}
+			}
+			v := e.View()
+			if v.Phase != tt.wantPhase || e.entry != tt.wantEntry || v.Battle != tt.wantBattle || (v.Message != "") != tt.wantMessage {
+				t.Errorf("phase %s entry %q battle %d message %q", v.Phase, e.entry, v.Battle, v.Message)
+			}
+			if x, ok := e.Session().Pending(); tt.wantPhase == usecase.PhaseConfirm && (!ok || x != tt.wantPending) {
+				t.Errorf("Pending = %v, %v, want %v", x, ok, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestEngine_OnTurn(t *testing.T) {
+	retry := [][]usecase.Action{{usecase.RetryAction}}
+	tests := []struct {
+		name        string
+		beforeStart bool // Start 前に登録する
+		src         *usecase.ScriptedInput
+		want        []int // 呼ばれたバトル番号
+	}{
+		{"registered before Start", true, frames(turn(0), turn(9), turn(5)), []int{1, 2, 3}},
+		{"registered after Start", false, frames(turn(0), turn(9), turn(5)), []int{1, 2, 3}},
+		{"kept across a retry", true, frames(turn(0), turn(0), turn(0), retry, turn(1)), []int{1, 2, 3, 1}},
+		{"no battle", false, frames([][]usecase.Action{{usecase.DigitAction(1)}}), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(tt.src)
+			var seen []int
+			register := func() {
+				e.OnTurn(func(turn usecase.TurnRecord) {
+					seen = append(seen, turn.Battle)
+					// ログはフックより先に書かれる
+					if v := e.View(); !strings.HasPrefix(v.Log[len(v.Log)-1], "Battle") && !strings.HasPrefix(v.Log[len(v.Log)-1], "[GAME") {
+						t.Errorf("last log line %q during OnTurn", v.Log[len(v.Log)-1])
+					}
+				})
+			}
+			if tt.beforeStart {
+				register()
+			}
+			config := testConfig()
+			player, enemy := config.NewCombatants()
+			if err := e.Start(13, "", config.BattleMax, player, enemy, usecase.InputDigits); err != nil {
+				t.Fatal(err)
+			}
+			if !tt.beforeStart {
+				register()
+			}
+			run(t, e, tt.src)
+			if !reflect.DeepEqual(seen, tt.want) {
+				t.Errorf("OnTurn saw battles %v, want %v", seen, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_Log(t *testing.T) {
+	tests := []struct {
+		name string
+		src  *usecase.ScriptedInput
+		edit func(e *Engine) // ゲームの後にログを操作する
+		want []string        // 末尾の行（nil なら空のログ）
+	}{
+		{"game ends with the outcome", frames(turn(3), turn(2), turn(5)), func(*Engine) {}, []string{"---", "[GAME WIN] Congratulations!"}},
+		{"AddLog appends", frames(turn(0)), func(e *Engine) { e.AddLog("note") }, []string{"note"}},
+		{"ClearLog empties", frames(turn(3), turn(2), turn(5)), func(e *Engine) { e.ClearLog() }, nil},
+		{"AddLog after ClearLog", frames(turn(0)), func(e *Engine) { e.ClearLog(); e.AddLog("a"); e.AddLog("b") }, []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := startEngine(t, tt.src, usecase.InputDigits)
+			run(t, e, tt.src)
+			tt.edit(e)
+			v := e.View()
+			if tt.want == nil {
+				if len(v.Log) != 0 {
+					t.Errorf("log %q, want empty", v.Log)
+				}
+				return
+			}
+			if len(v.Log) < len(tt.want) || !reflect.DeepEqual(v.Log[len(v.Log)-len(tt.want):], tt.want) {
+				t.Errorf("log %q, want it to end with %q", v.Log, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_Retry(t *testing.T) {
+	tests := []struct {
+		name       string
+		start      bool
+		src        *usecase.ScriptedInput
+		wantErr    error // nil 以外なら errors.Is で比較
+		wantReject bool  // 遷移表が拒否する（TransitionError）
+	}{
+		// Start 前の Step はフレームを読むだけ
+		{"before Start", false, frames(turn(0)), ErrNoGame, false},
+		{"after the game", true, frames(turn(0), turn(0), turn(0)), nil, false},
+		{"in the input phase", true, frames(turn(0)), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(tt.src)
+			if tt.start {
+				e = startEngine(t, tt.src, usecase.InputDigits)
+			}
+			run(t, e, tt.src)
+			before := e.View()
+			err := e.Retry()
+			var te *usecase.TransitionError
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Retry = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantReject:
+				if !errors.As(err, &te) {
+					t.Errorf("Retry = %v, want TransitionError", err)
+				}
+				if v := e.View(); v.Phase != before.Phase {
+					t.Errorf("phase %s after a rejected Retry, want %s", v.Phase, before.Phase)
+				}
+			default:
+				if err != nil {
+					t.Fatal(err)
+				}
+				if v := e.View(); v.Phase != usecase.PhaseInput || v.Battle != 0 || v.Over || len(v.Log) != 0 {
+					t.Errorf("after Retry: %+v", v)
+				}
+			}
+		})
+	}
+}
+
+func TestNew_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		call func()
+	}{
+		{"nil input", func() { New(nil) }},
+		{"Start with zero battles", func() {
+			player, enemy := testConfig().NewCombatants()
+			_ = New(usecase.ScriptActions()).Start(1, "", 0, player, enemy, usecase.InputDigits)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("should panic")
+				}
+			}()
+			tt.call()
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{FormatFloat(0.125), "0.12"},
+		{FormatFloat(-1), "-1.00"},
+		{WinLose(true), "WIN"},
+		{WinLose(false), "LOSE"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
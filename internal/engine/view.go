@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/usecase"
+	"fmt"
+)
+
+// ViewModel is everything a renderer needs to draw one frame of the game.
+// Slices are copies; renderers may keep them.
+type ViewModel struct {
+	Phase     usecase.Phase
+	Log       []string    // 古い順のバトルログ
+	Player    [][]float64 // プレイヤー行列。ゲーム開始前は nil
+	Enemy     [][]float64 // 敵行列。ゲーム開始前は nil
+	Result    float64     // 直近のバトルの結果値（HasResult のときのみ）
+	HasResult bool
+	Prompt    string // 画面下部の指示文（ゲーム中のみ）
+	Message   string // 入力のエラー表示
+	Seed      int64
+	ShareCode string
+	Battle    int // 終わったバトル数
+	BattleMax int
+	Wins      int  // 勝ったバトル数
+	Over      bool // 最終戦まで終わったか
+	Win       bool // ゲームの勝敗（Over のときのみ）
+}
+
+// View describes the current frame.
+func (e *Engine) View() ViewModel {
+	v := ViewModel{
+		Phase:   e.phase.Current(),
+		Log:     append([]string(nil), e.log...),
+		Message: e.message,
+	}
+	s := e.session
+	if s == nil {
+		return v
+	}
+	r := s.Result()
+	v.Player = domain.CopyRows(s.Player().GetMatrix().Data)
+	v.Enemy = domain.CopyRows(s.Enemy().GetMatrix().Data)
+	if turn, ok := s.LastTurn(); ok {
+		v.Result, v.HasResult = turn.Result, true
+	}
+	v.Seed, v.ShareCode = s.Seed(), e.shareCode
+	v.Battle, v.BattleMax = len(r.Turns), r.BattleMax
+	for _, t := range r.Turns {
+		if t.Win {
+			v.Wins++
+		}
+	}
+	v.Over, v.Win = r.Over, r.Win
+	v.Prompt = e.prompt()
+	return v
+}
+
+// prompt: フェーズごとの指示文。キーはどのフロントエンドでも 0-9 / Enter / Backspace / R
+func (e *Engine) prompt() string {
+	granularity := e.session.Granularity()
+	switch e.phase.Current() {
+	case usecase.PhaseInput:
+		switch granularity {
+		case usecase.InputDigits:
+			return "Press 0-9 to input"
+		case usecase.InputPercent:
+			return "Type 0-99 or 0.00-1.00, Enter: > " + e.entry + "_"
+		default:
+			return "Type a number from 0 to 1, Enter: > " + e.entry + "_"
+		}
+	case usecase.PhaseConfirm:
+		x, _ := e.session.Pending()
+		return fmt.Sprintf("Input: %s  [Enter: OK / Backspace: Re-input]", granularity.FormatInput(x))
+	case usecase.PhaseBattle:
+		return "Battle processing..."
+	case usecase.PhaseEnd:
+		return "GAME " + WinLose(e.session.Result().Win) + "! R: retry same rule"
+	}
+	return ""
+}
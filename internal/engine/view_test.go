@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"axiom_shift/internal/usecase"
+)
+
+func TestEngine_View(t *testing.T) {
+	tests := []struct {
+		name        string
+		beforeStart bool // Start せずに表示する
+		granularity usecase.InputGranularity
+		src         *usecase.ScriptedInput
+		typed       string
+		toMenu      bool // 操作の後でメニューへ戻る
+		wantPhase   usecase.Phase
+		wantPrompt  string
+		wantBattle  int
+		wantResult  bool
+	}{
+		{"before start", true, usecase.InputDigits, frames(), "", false, usecase.PhaseMenu, "", 0, false},
+		{"input digits", false, usecase.InputDigits, frames(), "", false, usecase.PhaseInput, "Press 0-9 to input", 0, false},
+		{"input percent", false, usecase.InputPercent, frames(), "4", false, usecase.PhaseInput, "Type 0-99 or 0.00-1.00, Enter: > 4_", 0, false},
+		{"input continuous", false, usecase.InputContinuous, frames(), "0.", false, usecase.PhaseInput, "Type a number from 0 to 1, Enter: > 0._", 0, false},
+		{"confirm", false, usecase.InputDigits, frames([][]usecase.Action{{usecase.DigitAction(7)}}), "", false, usecase.PhaseConfirm, "Input: 7  [Enter: OK / Backspace: Re-input]", 0, false},
+		{"battle", false, usecase.InputDigits, frames([][]usecase.Action{{usecase.DigitAction(7)}, {usecase.ConfirmAction}}), "", false, usecase.PhaseBattle, "Battle processing...", 0, false},
+		{"after a battle", false, usecase.InputDigits, frames(turn(7)), "", false, usecase.PhaseInput, "Press 0-9 to input", 1, true},
+		// メニューなどゲーム外のフェーズでは指示文を出さない
+		{"menu after a game", false, usecase.InputDigits, frames(turn(0), turn(0), turn(0)), "", true, usecase.PhaseMenu, "", 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(tt.src)
+			if !tt.beforeStart {
+				e = startEngine(t, tt.src, tt.granularity)
+			}
+			e.AddLog("hello")
+			e.TypeEntry(tt.typed)
+			run(t, e, tt.src)
+			if tt.toMenu {
+				if err := e.Phases().Transition(usecase.PhaseMenu); err != nil {
+					t.Fatal(err)
+				}
+			}
+			v := e.View()
+			if v.Phase != tt.wantPhase || v.Prompt != tt.wantPrompt {
+				t.Errorf("phase %s prompt %q, want %s %q", v.Phase, v.Prompt, tt.wantPhase, tt.wantPrompt)
+			}
+			if v.Battle != tt.wantBattle || v.HasResult != tt.wantResult {
+				t.Errorf("view %+v", v)
+			}
+			if tt.beforeStart {
+				if v.Player != nil || v.Enemy != nil || !reflect.DeepEqual(v.Log, []string{"hello"}) {
+					t.Errorf("view before Start %+v", v)
+				}
+				return
+			}
+			if v.Seed != 13 || v.ShareCode != "CODE" || v.BattleMax != 3 {
+				t.Errorf("view %+v", v)
+			}
+			if tt.wantResult {
+				last, _ := e.Session().LastTurn()
+				if v.Result != last.Result {
+					t.Errorf("Result = %v, want %v", v.Result, last.Result)
+				}
+			}
+			if !reflect.DeepEqual(v.Player, e.Session().Player().GetMatrix().Data) || !reflect.DeepEqual(v.Enemy, e.Session().Enemy().GetMatrix().Data) {
+				t.Errorf("matrices %v / %v", v.Player, v.Enemy)
+			}
+			// 表示内容はコピーで、書き換えてもエンジンの状態は変わらない
+			v.Player[0][0] = 42
+			v.Enemy[0][0] = 42
+			v.Log[0] = "changed"
+			if w := e.View(); w.Player[0][0] == 42 || w.Enemy[0][0] == 42 || w.Log[0] == "changed" {
+				t.Error("View shares state with the engine")
+			}
+		})
+	}
+}
+
+func TestEngine_ViewEnd(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        *usecase.ScriptedInput
+		wantWin    bool
+		wantPrompt string
+		wantLast   string
+	}{
+		{"win", frames(turn(3), turn(2), turn(5)), true, "GAME WIN! R: retry same rule", "[GAME WIN] Congratulations!"},
+		{"lose", frames(turn(0), turn(2), turn(2)), false, "GAME LOSE! R: retry same rule", "[GAME LOSE] Try again!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := startEngine(t, tt.src, usecase.InputDigits)
+			run(t, e, tt.src)
+			v := e.View()
+			wins := 0
+			for _, turn := range e.Session().Result().Turns {
+				if turn.Win {
+					wins++
+				}
+			}
+			if v.Phase != usecase.PhaseEnd || !v.Over || v.Win != tt.wantWin || v.Wins != wins || v.Battle != 3 || v.Prompt != tt.wantPrompt {
+				t.Errorf("view %+v", v)
+			}
+			if last := v.Log[len(v.Log)-1]; last != tt.wantLast {
+				t.Errorf("last log line %q, want %q", last, tt.wantLast)
+			}
+		})
+	}
+}
@@ -2,189 +2,349 @@ package game
 
 import (
 	"axiom_shift/internal/domain"
+	"axiom_shift/internal/engine"
+	"axiom_shift/internal/logic"
 	"axiom_shift/internal/ui"
 	"axiom_shift/internal/usecase"
+	"context"
 	"fmt"
 	"image/color"
+	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type Game struct {
-	battleCount int
-	battleMax   int
-	player      *domain.Player
-	enemy       *domain.Enemy
-	rule        *domain.RuleMatrix
-	ui          UIInterface
-	inputValue  int
-	phase       string   // "input", "confirm", "battle", "end"
-	lastWin     bool     // 最終戦の勝敗記録
-	seed        int64    // ルール生成用シード値
-	lastResult  *float64 // 直近バトルの結果値（-1.0〜+1.0想定）
+	config     usecase.GameConfig // 行列・成長率の設定（seed bank のキーに使う）
+	battleMax  int                // 次に開始するゲームのバトル数
+	player     *domain.Player
+	enemy      *domain.Enemy
+	engine     *engine.Engine // ゲームの進行・ログ・表示内容。画面のフェーズもここにある
+	ui         UIInterface
+	scenes     *sceneManager      // 底がタイトルのシーンスタック
+	report     usecase.SeedReport // seed の探索・検証結果
+	difficulty int                // 共有コードに含める難易度
+	seedEntry  string             // メニューで入力中の seed / 共有コード
+	verifySeed bool               // 入力 seed の妥当性チェックを行うか
+	target     usecase.Difficulty // ランダム seed の探索で狙う難易度（Any なら制限なし）
+	menuMsg    string             // メニューに表示するエラー等
+	daily      *usecase.DailyChallenge
+	dailyMode  bool        // デイリーチャレンジ中か
+	dailyDay   time.Time   // 遊んでいるデイリーの日付（開始時に決め、日付をまたいでも変えない）
+	search     *seedSearch // loading フェーズで実行中の探索
+	bank       *usecase.SeedBank
+	bankPath   string                  // 空なら bank を保存しない
+	reveal     *usecase.SolutionReplay // 敗北後に表示する勝ち筋（reveal フェーズ）
 }
 
 type UIInterface interface {
-	AddBattleLog(string)
-	ClearBattleLog()
-	Draw(screen *ebiten.Image)
+	Draw(screen *ebiten.Image, log []string)
 }
 
+// seedEntryMaxLen: 共有コード（ダッシュ込み 29 文字）が余裕を持って入る長さ
+const seedEntryMaxLen = 40
+
+// NewGame: キーボード入力で遊ぶゲーム
 func NewGame() *Game {
-	player := domain.NewPlayer(domain.NewMatrix([][]float64{
-		{2.0, 0.0, 0.0},
-		{0.0, 2.0, 0.0},
-		{0.0, 0.0, 2.0},
-	}), 0.5)
-	enemy := domain.NewEnemy("Enemy", domain.NewMatrix([][]float64{
-		{0.0, 0.0, 2.0},
-		{0.0, 2.0, 0.0},
-		{2.0, 0.0, 0.0},
-	}), 0.5)
-	battleMax := 10
-	seed, playerPath, enemyPath, err := usecase.FindValidSeed(battleMax, player, enemy)
+	return NewGameWithInput(ebitenInput{})
+}
+
+// NewGameWithInput: ゲーム中の操作（数字・決定・取り消し・リトライ）を input から受け取るゲーム
+// テキスト入力（seed・細かい粒度の入力）とメニュー操作はキーボードから読む
+func NewGameWithInput(input usecase.InputSource) *Game {
+	config := usecase.DefaultGameConfig()
+	player, enemy := config.NewCombatants()
+	ui := ui.NewUI()
+	daily, err := usecase.NewDailyChallenge(time.Now, usecase.UserDataPath("daily.json"))
 	if err != nil {
-		panic(fmt.Sprintf("Seed search failed: %v", err))
+		// 記録ファイルが壊れている場合は上書きしないようメモリ上のみで記録する
+		daily, _ = usecase.NewDailyChallenge(time.Now, "")
 	}
-	rule := domain.NewRuleMatrix(seed, player.MatrixState.Rows)
-	player.Reset()
-	enemy.Reset()
-	_ = playerPath
-	_ = enemyPath
-	ui := ui.NewUI()
-	ui.ClearBattleLog()
-	return &Game{
-		battleCount: 0,
-		battleMax:   battleMax,
-		player:      player,
-		enemy:       enemy,
-		rule:        rule,
-		ui:          ui,
-		phase:       "input",
-		lastWin:     false,
-		seed:        seed,
+	bankPath := usecase.UserDataPath("seedbank.json")
+	bank := usecase.NewSeedBank()
+	if bankPath != "" {
+		if loaded, err := usecase.LoadSeedBank(bankPath); err == nil {
+			bank = loaded
+		} else {
+			bankPath = "" // 壊れた bank は上書きしない
+		}
 	}
+	g := &Game{
+		config:     config,
+		battleMax:  config.BattleMax,
+		player:     player,
+		enemy:      enemy,
+		engine:     engine.New(input),
+		ui:         ui,
+		verifySeed: true,
+		daily:      daily,
+		bank:       bank,
+		bankPath:   bankPath,
+	}
+	g.registerPhaseHooks(g.engine.Phases())
+	g.engine.OnTurn(g.recordDaily)
+	g.scenes = newSceneManager(&titleScene{g: g}, fadeFrames)
+	return g
 }
 
-// formatFloat: 全ての数値出力を統一的に整形できるメリットがあるため利用
-func (g *Game) Update() error {
-	switch g.phase {
-	case "input":
-		// キー入力受付: 0-9キーで0.0-1.0にマッピング
-		for i := 0; i <= 9; i++ {
-			if ebiten.IsKeyPressed(ebiten.Key0 + ebiten.Key(i)) {
-				g.inputValue = i
-				g.phase = "confirm"
-				break
-			}
+// registerPhaseHooks: フェーズに入る・出るときにシーンを切り替え、後始末をする
+// 入力欄・ログの片付けはエンジンが行う
+func (g *Game) registerPhaseHooks(m *usecase.PhaseMachine) {
+	m.OnEnter(usecase.PhaseMenu, func(usecase.Phase) {
+		g.scenes.PopUntil(isMenuScene)
+	})
+	m.OnEnter(usecase.PhaseLoading, func(usecase.Phase) {
+		g.scenes.Push(&loadingScene{g: g, search: g.search})
+	})
+	m.OnExit(usecase.PhaseLoading, func(usecase.Phase) {
+		if g.search != nil { // 完了前に抜けた探索は止める
+			g.search.cancel()
+			g.search = nil
+		}
+		g.scenes.Pop()
+	})
+	m.OnEnter(usecase.PhaseInput, func(from usecase.Phase) {
+		switch from {
+		case usecase.PhaseConfirm, usecase.PhaseBattle: // ゲーム中は同じ画面のまま
+		default: // ゲーム開始・リトライ
+			g.scenes.PopUntil(isTitle)
+			g.scenes.Push(&playScene{g: g})
+		}
+	})
+	m.OnEnter(usecase.PhaseEnd, func(from usecase.Phase) {
+		if from == usecase.PhaseBattle {
+			g.scenes.Replace(&resultsScene{g: g})
+		}
+	})
+	m.OnEnter(usecase.PhaseReveal, func(usecase.Phase) {
+		g.scenes.Push(newReplayScene(g, g.reveal))
+	})
+	m.OnExit(usecase.PhaseReveal, func(usecase.Phase) {
+		g.reveal = nil
+		g.scenes.Pop()
+	})
+}
+
+// setPhase: 遷移表に無い遷移はバグなので panic する
+func (g *Game) setPhase(p usecase.Phase) {
+	if err := g.engine.Phases().Transition(p); err != nil {
+		panic(err.Error())
+	}
+}
+
+// backToMenu: メッセージを出してメニューへ戻る（メニューからの開始に失敗した場合はそのまま留まる）
+func (g *Game) backToMenu(msg string) {
+	if g.engine.Phases().Current() != usecase.PhaseMenu {
+		g.setPhase(usecase.PhaseMenu)
+	}
+	g.menuMsg = msg
+}
+
+// resetSetup: 共有コードで変えた設定（バトル数）を標準設定に戻す
+// デイリー・ランダム seed・同梱 seed は常に標準設定で遊ぶ
+func (g *Game) resetSetup() {
+	config := usecase.DefaultGameConfig()
+	g.battleMax = config.BattleMax
+	g.player, g.enemy = config.NewCombatants()
+}
+
+// startDaily: 今日の日付から決まる seed でデイリーチャレンジを開始する
+// 全員が同じ問題を遊べるよう、直前に読み込んだ共有コードの設定は使わない
+func (g *Game) startDaily() {
+	g.resetSetup()
+	battleMax := g.battleMax
+	player, enemy := g.player.Clone(), g.enemy.Clone()
+	day := g.daily.Now()
+	g.beginSearch("Searching for the daily seed "+usecase.DailyKey(day), func(ctx context.Context, progress func(usecase.SeedProgress)) seedSearchResult {
+		report, err := g.daily.FindSeed(ctx, day, battleMax, player, enemy, usecase.SeedSearchOptions{Progress: progress})
+		return seedSearchResult{report: report, err: err}
+	}, func(r seedSearchResult) {
+		if r.err != nil {
+			g.backToMenu(fmt.Sprintf("Daily seed search failed: %v", r.err))
+			return
 		}
-	case "confirm":
-		// Enterでバトルへ、Backspaceで再入力
-		if ebiten.IsKeyPressed(ebiten.KeyEnter) {
-			g.phase = "battle"
-		} else if ebiten.IsKeyPressed(ebiten.KeyBackspace) {
-			g.phase = "input"
+		g.difficulty = int(r.report.Difficulty())
+		if err := g.start(r.report); err != nil {
+			g.backToMenu(err.Error())
+			return
 		}
-	case "battle":
-		service := usecase.NewBattleService(g.player, g.enemy, g.rule)
-		result, win := service.DoBattleTurn(float64(g.inputValue)/9, g.battleCount)
-		g.lastResult = &result
-		g.battleCount++
-		log := fmt.Sprintf("Battle %d: Input=%d Result=%s Win/Lose=%s", g.battleCount, g.inputValue, formatFloat(result), winLoseStrEN(win))
-		g.ui.AddBattleLog(log)
-		if g.battleCount >= g.battleMax {
-			g.phase = "end"
-			g.ui.AddBattleLog("---")
-			g.lastWin = win
-			if win {
-				g.ui.AddBattleLog("[GAME WIN] Congratulations!")
-			} else {
-				g.ui.AddBattleLog("[GAME LOSE] Try again!")
+		g.dailyMode = true
+		g.dailyDay = day
+	})
+}
+
+// startRandomSeed: 選択中の難易度を満たす seed を bank から取り出してゲームを開始する
+// bank が空なら探索する
+func (g *Game) startRandomSeed() {
+	g.resetSetup()
+	battleMax := g.battleMax
+	criteria := usecase.CriteriaForDifficulty(g.target)
+	config := g.config
+	config.BattleMax = battleMax
+	key := usecase.SeedBankKey(config, criteria)
+	if report, ok := g.bank.Take(key, logic.NewSeedManager()); ok {
+		g.dailyMode = false
+		g.difficulty = int(report.Difficulty())
+		if err := g.start(report); err == nil {
+			g.engine.AddLog(fmt.Sprintf("[Notice] Seed taken from the bank (%d left)", g.bank.Len(key)))
+			if g.bankPath != "" {
+				if err := g.bank.Save(g.bankPath); err != nil {
+					g.engine.AddLog(fmt.Sprintf("[Notice] Seed bank not saved: %v", err))
+				}
 			}
-		} else {
-			g.phase = "input"
+			return
 		}
-	case "end":
-		// Rキーでリトライ
-		if ebiten.IsKeyPressed(ebiten.KeyR) {
-			g.Reset()
+	}
+
+	player, enemy := g.player.Clone(), g.enemy.Clone()
+	g.beginSearch(fmt.Sprintf("Searching for a new random seed (%s)", g.target), func(ctx context.Context, progress func(usecase.SeedProgress)) seedSearchResult {
+		report, err := usecase.FindSeed(ctx, battleMax, player, enemy, logic.NewSeedManager(), criteria, usecase.SeedSearchOptions{Progress: progress, Granularity: config.Granularity})
+		return seedSearchResult{report: report, err: err}
+	}, func(r seedSearchResult) {
+		g.dailyMode = false
+		if r.err != nil {
+			g.startFallback(r.err)
+			return
+		}
+		g.difficulty = int(r.report.Difficulty())
+		if err := g.start(r.report); err != nil {
+			g.startFallback(err)
 		}
+	})
+}
+
+// startFallback: 探索に失敗した場合、同梱の検証済み seed（標準設定用）で開始する
+func (g *Game) startFallback(cause error) {
+	g.resetSetup()
+	g.difficulty = int(usecase.DifficultyAny)
+	if err := g.start(usecase.SeedReport{Seed: usecase.FallbackSeed}); err != nil {
+		panic(fmt.Sprintf("Share code encoding failed: %v", err))
+	}
+	g.engine.AddLog(fmt.Sprintf("[Notice] Seed search failed (%v); using bundled seed", cause))
+}
+
+// submitSeedEntry: メニューの入力内容を検証し、問題なければその seed で開始する
+func (g *Game) submitSeedEntry() {
+	if strings.TrimSpace(g.seedEntry) == "" {
+		g.startRandomSeed()
+		return
+	}
+	// 数値の seed は標準のバトル数で遊ぶ（前に読み込んだ共有コードのバトル数は引き継がない）
+	size := g.player.MatrixState.Rows
+	code, err := logic.ParseSeedInput(g.seedEntry, logic.ShareCode{Size: size, BattleMax: usecase.DefaultGameConfig().BattleMax, Generator: logic.GeneratorUniform})
+	if err == nil {
+		err = code.Validate(size)
+	}
+	if err != nil {
+		g.menuMsg = err.Error()
+		return
+	}
+	startCode := func(report usecase.SeedReport) {
+		g.battleMax = code.BattleMax
+		g.difficulty = code.Difficulty
+		g.dailyMode = false
+		if err := g.start(report); err != nil {
+			g.backToMenu(err.Error())
+		}
+	}
+	if !g.verifySeed {
+		startCode(usecase.SeedReport{Seed: code.Seed})
+		return
+	}
+	player, enemy := g.player.Clone(), g.enemy.Clone()
+	g.beginSearch(fmt.Sprintf("Checking seed %d", code.Seed), func(ctx context.Context, progress func(usecase.SeedProgress)) seedSearchResult {
+		report, ok := usecase.CheckSeed(code.Seed, code.BattleMax, player, enemy)
+		return seedSearchResult{report: report, ok: ok}
+	}, func(r seedSearchResult) {
+		if !r.ok {
+			g.backToMenu("Seed rejected by validity check (Tab to skip the check)")
+			return
+		}
+		startCode(r.report)
+	})
+}
+
+// start: seed を確定し、共有コードを作り直して入力フェーズから始める
+// report は探索・検証を経ていない seed では Seed 以外が空になる
+func (g *Game) start(report usecase.SeedReport) error {
+	seed := report.Seed
+	shareCode, err := logic.ShareCode{
+		Seed:       seed,
+		Size:       g.player.MatrixState.Rows,
+		BattleMax:  g.battleMax,
+		Difficulty: g.difficulty,
+		Generator:  logic.GeneratorUniform,
+	}.Encode()
+	if err != nil {
+		return err
+	}
+	g.report = report
+	g.seedEntry = ""
+	g.menuMsg = ""
+	if err := g.engine.Start(seed, shareCode, g.battleMax, g.player, g.enemy, g.config.Granularity); err != nil {
+		panic(err.Error()) // 遷移表に無い遷移はバグ
 	}
 	return nil
 }
 
-func formatFloat(f float64) string {
-	return fmt.Sprintf("%.2f", f)
+// Update: 一番上のシーンに任せる
+func (g *Game) Update() error {
+	return g.scenes.Update()
 }
 
+// Draw: 一番上のシーンに任せる（切り替え中はフェードを重ねる）
 func (g *Game) Draw(screen *ebiten.Image) {
-	switch g.phase {
-	case "input":
-		g.ui.Draw(screen)
-		// 指示文を画面下部に表示
-		ui.DrawText(screen, "Press 0-9 to input", 10, 460)
-	case "confirm":
-		g.ui.Draw(screen)
-		msg := fmt.Sprintf("Input: %d  [Enter: OK / Backspace: Re-input]", g.inputValue)
-		ui.DrawText(screen, msg, 10, 460)
-	case "battle":
-		g.ui.Draw(screen)
-		ui.DrawText(screen, "Battle processing...", 10, 460)
-	case "end":
-		g.ui.Draw(screen)
-		if g.lastWin {
-			ui.DrawText(screen, "GAME WIN! Press R to retry same rule.", 10, 460)
-		} else {
-			ui.DrawText(screen, "GAME LOSE! Press R to retry same rule.", 10, 460)
-		}
-	}
+	g.scenes.Draw(screen)
+}
+
+// drawBoard: ゲーム中の共通表示（seed・共有コード・結果バー・行列）
+func (g *Game) drawBoard(screen *ebiten.Image, vm engine.ViewModel) {
 	// 画面右下にSeed値を表示
-	seedMsg := fmt.Sprintf("Seed: %d", g.seed)
+	seedMsg := fmt.Sprintf("Seed: %d", vm.Seed)
 	ui.DrawText(screen, seedMsg, 485, 460)
+	ui.DrawText(screen, "Code: "+vm.ShareCode, 425, 440)
+	if g.dailyMode {
+		ui.DrawText(screen, fmt.Sprintf("Daily %s  Streak: %d", usecase.DailyKey(g.dailyDay), g.daily.Streak()), 425, 10)
+	}
+	if g.report.Deep.Samples > 0 {
+		ui.DrawText(screen, fmt.Sprintf("Difficulty: %s (random win %.0f%%)", g.report.Difficulty(), g.report.Deep.Rate*100), 425, 30)
+	}
 	// 画面中央下にResultバーを描画
-	if g.lastResult != nil {
-		drawResultBar(screen, *g.lastResult)
+	if vm.HasResult {
+		drawResultBar(screen, vm.Result)
 	}
 	// --- Player/Enemy行列のビジュアライズ ---
 	startX, startY := 200, 310 // 画面下部のテキストの上
-	if g.player != nil && g.player.MatrixState != nil {
-		mat := g.player.MatrixState
-		cellSize := 18
-		margin := 3
-		for i := 0; i < mat.Rows; i++ {
-			for j := 0; j < mat.Cols; j++ {
-				v := mat.Data[i][j]
-				if v < 0 {
-					v = 0
-				}
-				if v > 1 {
-					v = 1
-				}
-				clr := color.RGBA{0, 0, uint8(64 + 191*v), 255} // 青の濃さ
-				drawRect(screen, float64(startX+j*(cellSize+margin)), float64(startY+i*(cellSize+margin)), float64(cellSize), float64(cellSize), clr)
-			}
-		}
+	if vm.Player != nil {
+		drawMatrix(screen, vm.Player, startX, startY, true)
 		ui.DrawText(screen, "Player", startX, startY-18)
 	}
 	startX += 180
-	if g.enemy != nil && g.enemy.MatrixState != nil {
-		mat := g.enemy.MatrixState
-		cellSize := 18
-		margin := 3
-		for i := 0; i < mat.Rows; i++ {
-			for j := 0; j < mat.Cols; j++ {
-				v := mat.Data[i][j]
-				if v < 0 {
-					v = 0
-				}
-				if v > 1 {
-					v = 1
-				}
-				clr := color.RGBA{uint8(64 + 191*v), 0, 0, 255} // 赤の濃さ
-				drawRect(screen, float64(startX+j*(cellSize+margin)), float64(startY+i*(cellSize+margin)), float64(cellSize), float64(cellSize), clr)
+	if vm.Enemy != nil {
+		drawMatrix(screen, vm.Enemy, startX, startY, false)
+		ui.DrawText(screen, "Enemy", startX, startY-18)
+	}
+}
+
+// drawMatrix: 行列の各成分を濃さで描く（プレイヤーは青、敵は赤）
+func drawMatrix(screen *ebiten.Image, data [][]float64, x, y int, player bool) {
+	cellSize := 18
+	margin := 3
+	for i, row := range data {
+		for j, v := range row {
+			if v < 0 {
+				v = 0
+			}
+			if v > 1 {
+				v = 1
+			}
+			clr := color.RGBA{0, 0, uint8(64 + 191*v), 255} // 青の濃さ
+			if !player {
+				clr = color.RGBA{uint8(64 + 191*v), 0, 0, 255} // 赤の濃さ
 			}
+			drawRect(screen, float64(x+j*(cellSize+margin)), float64(y+i*(cellSize+margin)), float64(cellSize), float64(cellSize), clr)
 		}
-		ui.DrawText(screen, "Enemy", startX, startY-18)
 	}
 }
 
@@ -199,9 +359,11 @@ func drawRect(screen *ebiten.Image, x, y, w, h float64, clr color.Color) {
 
 // 結果値をバーでビジュアライズ
 func drawResultBar(screen *ebiten.Image, result float64) {
-	barY := 410
-	barX := 120
-	barW := 400
+	drawResultBarAt(screen, result, 120, 410, 400)
+}
+
+// drawResultBarAt: 左上 (barX, barY)、幅 barW の結果バー
+func drawResultBarAt(screen *ebiten.Image, result float64, barX, barY, barW int) {
 	barH := 18
 	// 背景バー
 	drawRect(screen, float64(barX), float64(barY), float64(barW), float64(barH), color.RGBA{80, 80, 80, 255})
@@ -231,21 +393,9 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return 640, 480 // Set the game window size
 }
 
+// Reset: 同じ seed でゲームをやり直す
 func (g *Game) Reset() {
-	g.battleCount = 0
-	g.player.Reset()
-	g.enemy.Reset()
-	g.rule = domain.NewRuleMatrix(g.seed, g.player.MatrixState.Rows) // seedを再利用
-	g.ui.ClearBattleLog()
-	g.phase = "input"
-	g.lastWin = false
-	g.lastResult = nil
-}
-
-// winLoseStrEN: 英語表記の勝敗判定は今後も使うため残す
-func winLoseStrEN(win bool) string {
-	if win {
-		return "WIN"
-	}
-	return "LOSE"
+	if err := g.engine.Retry(); err != nil {
+		panic(err.Error())
+	}
 }
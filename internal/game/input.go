@@ -0,0 +1,30 @@
+package game
+
+import (
+	"axiom_shift/internal/usecase"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// ebitenInput: キーボードの押した瞬間だけを操作として返す（押しっぱなしでフェーズを飛ばさない）
+type ebitenInput struct{}
+
+func (ebitenInput) Actions() []usecase.Action {
+	var actions []usecase.Action
+	for i := 0; i <= 9; i++ {
+		if inpututil.IsKeyJustPressed(ebiten.Key0+ebiten.Key(i)) || inpututil.IsKeyJustPressed(ebiten.KeyNumpad0+ebiten.Key(i)) {
+			actions = append(actions, usecase.DigitAction(i))
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		actions = append(actions, usecase.ConfirmAction)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		actions = append(actions, usecase.CancelAction)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		actions = append(actions, usecase.RetryAction)
+	}
+	return actions
+}
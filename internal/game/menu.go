@@ -0,0 +1,165 @@
+package game
+
+import (
+	"axiom_shift/internal/ui"
+	"axiom_shift/internal/usecase"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// isTitle: ゲーム開始時はタイトルまで戻してからゲーム画面を積む
+func isTitle(s Scene) bool {
+	_, ok := s.(*titleScene)
+	return ok
+}
+
+// isMenuScene: メニューに戻るときは直前に開いていたメニュー画面（seed 入力など）まで戻す
+func isMenuScene(s Scene) bool {
+	switch s.(type) {
+	case *titleScene, *settingsScene, *seedEntryScene:
+		return true
+	}
+	return false
+}
+
+// titleScene: タイトルメニュー。スタックの底に常にある
+type titleScene struct {
+	g *Game
+}
+
+func (s *titleScene) Update() error {
+	g := s.g
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		g.startRandomSeed()
+	case inpututil.IsKeyJustPressed(ebiten.KeyC):
+		g.menuMsg = ""
+		g.scenes.Push(&seedEntryScene{g: g})
+	case inpututil.IsKeyJustPressed(ebiten.KeyS):
+		g.scenes.Push(&settingsScene{g: g})
+	case inpututil.IsKeyJustPressed(ebiten.KeyF1):
+		g.startDaily()
+	}
+	return nil
+}
+
+func (s *titleScene) Draw(screen *ebiten.Image) {
+	g := s.g
+	g.ui.Draw(screen, g.engine.View().Log)
+	ui.DrawText(screen, "AXIOM SHIFT", 10, 10)
+	ui.DrawText(screen, fmt.Sprintf("[Enter] New random seed (%s)", g.target), 10, 50)
+	ui.DrawText(screen, "[C] Enter a seed or share code", 10, 70)
+	ui.DrawText(screen, fmt.Sprintf("[F1] Daily challenge %s (streak: %d)", g.daily.Today(), g.daily.Streak()), 10, 90)
+	ui.DrawText(screen, "[S] Settings", 10, 110)
+	if g.menuMsg != "" {
+		ui.DrawText(screen, g.menuMsg, 10, 140)
+	}
+}
+
+// settingsScene: 難易度・入力粒度・seed の妥当性チェックの設定
+type settingsScene struct {
+	g      *Game
+	cursor int
+}
+
+// settingsRows: 設定画面の行数
+const settingsRows = 3
+
+func (s *settingsScene) Update() error {
+	g := s.g
+	delta := 0
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		g.scenes.Pop()
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		s.cursor = (s.cursor + 1) % settingsRows
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		s.cursor = (s.cursor + settingsRows - 1) % settingsRows
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight), inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		delta = 1
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft):
+		delta = -1
+	}
+	if delta == 0 {
+		return nil
+	}
+	switch s.cursor {
+	case 0:
+		n := int(usecase.DifficultyExpert) + 1
+		g.target = usecase.Difficulty((int(g.target) + delta + n) % n)
+	case 1:
+		n := int(usecase.InputContinuous) + 1
+		g.config.Granularity = usecase.InputGranularity((int(g.config.Granularity) + delta + n) % n)
+	case 2:
+		g.verifySeed = !g.verifySeed
+	}
+	return nil
+}
+
+func (s *settingsScene) Draw(screen *ebiten.Image) {
+	g := s.g
+	g.ui.Draw(screen, g.engine.View().Log)
+	ui.DrawText(screen, "SETTINGS", 10, 10)
+	check := "OFF"
+	if g.verifySeed {
+		check = "ON"
+	}
+	rows := []string{
+		fmt.Sprintf("Difficulty for random seeds: %s", g.target),
+		fmt.Sprintf("Input granularity: %s", g.config.Granularity),
+		fmt.Sprintf("Validity check for entered seeds: %s", check),
+	}
+	for i, row := range rows {
+		cursor := "  "
+		if i == s.cursor {
+			cursor = "> "
+		}
+		ui.DrawText(screen, cursor+row, 10, 50+i*20)
+	}
+	ui.DrawText(screen, "[Up/Down] Select  [Left/Right] Change  [Esc] Back", 10, 460)
+}
+
+// seedEntryScene: seed / 共有コードの入力欄
+type seedEntryScene struct {
+	g *Game
+}
+
+func (s *seedEntryScene) Update() error {
+	g := s.g
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if r >= 0x20 && r < 0x7f && len(g.seedEntry) < seedEntryMaxLen {
+			g.seedEntry += string(r)
+		}
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(g.seedEntry) > 0:
+		g.seedEntry = g.seedEntry[:len(g.seedEntry)-1]
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		g.verifySeed = !g.verifySeed
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		g.menuMsg = ""
+		g.scenes.Pop()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		g.submitSeedEntry()
+	}
+	return nil
+}
+
+func (s *seedEntryScene) Draw(screen *ebiten.Image) {
+	g := s.g
+	g.ui.Draw(screen, g.engine.View().Log)
+	ui.DrawText(screen, "Enter a seed or share code (empty: new random seed)", 10, 10)
+	ui.DrawText(screen, "> "+g.seedEntry+"_", 10, 40)
+	check := "OFF"
+	if g.verifySeed {
+		check = "ON"
+	}
+	ui.DrawText(screen, fmt.Sprintf("[Tab] Validity check: %s", check), 10, 70)
+	ui.DrawText(screen, fmt.Sprintf("Input granularity: %s  Difficulty for random seeds: %s  ([S] on the title)", g.config.Granularity, g.target), 10, 90)
+	if g.menuMsg != "" {
+		ui.DrawText(screen, g.menuMsg, 10, 120)
+	}
+	ui.DrawText(screen, "[Enter] Start  [Esc] Back", 10, 460)
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"axiom_shift/internal/ui"
+	"axiom_shift/internal/usecase"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// playScene: 入力・確認・バトルのフェーズを受け持つゲーム画面
+type playScene struct {
+	g *Game
+}
+
+// Update: 10 キー以外の粒度では打った文字を入力欄に渡し、あとはエンジンに任せる
+func (s *playScene) Update() error {
+	e := s.g.engine
+	if e.Phases().Current() == usecase.PhaseInput && e.Session().Granularity() != usecase.InputDigits {
+		e.TypeEntry(string(ebiten.AppendInputChars(nil)))
+	}
+	return e.Step()
+}
+
+// recordDaily: デイリーチャレンジの最終戦なら結果を記録する
+func (g *Game) recordDaily(turn usecase.TurnRecord) {
+	s := g.engine.Session()
+	if !g.dailyMode || !s.Over() {
+		return
+	}
+	if err := g.daily.Record(g.dailyDay, s.Result().Win, turn.Result); err != nil {
+		g.engine.AddLog(fmt.Sprintf("[Daily] Failed to save result: %v", err))
+	}
+	g.engine.AddLog(fmt.Sprintf("[Daily %s] Streak: %d", usecase.DailyKey(g.dailyDay), g.daily.Streak()))
+}
+
+func (s *playScene) Draw(screen *ebiten.Image) {
+	g := s.g
+	vm := g.engine.View()
+	g.ui.Draw(screen, vm.Log)
+	// 指示文を画面下部に表示
+	ui.DrawText(screen, vm.Prompt, 10, 460)
+	if vm.Message != "" {
+		ui.DrawText(screen, vm.Message, 10, 440)
+	}
+	g.drawBoard(screen, vm)
+}
+
+// resultsScene: 最終戦の後の画面。勝敗の要約と次の行動の選択
+type resultsScene struct {
+	g *Game
+}
+
+func (s *resultsScene) Update() error {
+	g := s.g
+	// リトライ（R）は GameLoop が扱う。Nキーで新しいランダム seed、Mキーでメニューへ、敗北時は Sキーで勝ち筋を表示
+	if err := g.engine.Step(); err != nil || g.engine.Phases().Current() != usecase.PhaseEnd {
+		return err
+	}
+	switch {
+	case !g.engine.Session().Result().Win && inpututil.IsKeyJustPressed(ebiten.KeyS):
+		g.showSolution()
+	case inpututil.IsKeyJustPressed(ebiten.KeyN):
+		g.startRandomSeed()
+	case inpututil.IsKeyJustPressed(ebiten.KeyM):
+		g.engine.ClearLog()
+		g.seedEntry = ""
+		g.dailyMode = false
+		g.setPhase(usecase.PhaseMenu)
+	}
+	return nil
+}
+
+func (s *resultsScene) Draw(screen *ebiten.Image) {
+	g := s.g
+	vm := g.engine.View()
+	g.ui.Draw(screen, vm.Log)
+	ui.DrawText(screen, fmt.Sprintf("Battles won: %d/%d", vm.Wins, vm.Battle), 10, 440)
+	prompt := vm.Prompt + " / N: new seed / M: menu"
+	if !vm.Win {
+		prompt += " / S: show a winning line"
+	}
+	ui.DrawText(screen, prompt, 10, 460)
+	g.drawBoard(screen, vm)
+}
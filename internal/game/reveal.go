@@ -0,0 +1,111 @@
+package game
+
+import (
+	"axiom_shift/internal/engine"
+	"axiom_shift/internal/ui"
+	"axiom_shift/internal/usecase"
+	"context"
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// showSolution: 敗北後、既知の勝ち筋と実際の入力を並べて 1 ターンずつ再生する画面へ移る
+// seed の探索結果に勝ち筋が無ければ（検証なしの共有コード・同梱 seed）loading 画面で探す。Esc で終了画面へ戻る
+func (g *Game) showSolution() {
+	s := g.engine.Session()
+	if solution := g.report.WinningInputs(); len(solution) == s.BattleMax() {
+		g.revealSolution(solution)
+		return
+	}
+	// 探索ゴルーチンにはセッションの行列を渡さず複製を渡す
+	seed, battleMax, player, enemy, granularity := s.Seed(), s.BattleMax(), s.Player().Clone(), s.Enemy().Clone(), s.Granularity()
+	g.beginTask("Searching for a winning line", func(ctx context.Context, _ func(usecase.SeedProgress)) seedSearchResult {
+		inputs, ok, err := usecase.WinningPath(ctx, seed, battleMax, player, enemy, granularity)
+		return seedSearchResult{inputs: inputs, ok: ok, err: err}
+	}, func(r seedSearchResult) {
+		g.setPhase(usecase.PhaseEnd)
+		if r.err != nil || !r.ok {
+			g.engine.AddLog("[Notice] No winning line found for this seed")
+			return
+		}
+		g.revealSolution(r.inputs)
+	}, func() {
+		g.setPhase(usecase.PhaseEnd)
+	})
+}
+
+// revealSolution: 勝ち筋と実際の入力を比べる画面へ移る
+func (g *Game) revealSolution(solution []float64) {
+	s := g.engine.Session()
+	r := usecase.CompareWithSolution(s.Seed(), s.Player(), s.Enemy(), solution, s.Inputs())
+	g.reveal = &r
+	g.setPhase(usecase.PhaseReveal)
+}
+
+// replayScene: 勝ち筋と実際の入力を 1 ターンずつ見比べる画面
+type replayScene struct {
+	g      *Game
+	replay *usecase.SolutionReplay
+	turn   int // 表示中のターン
+}
+
+func newReplayScene(g *Game, r *usecase.SolutionReplay) *replayScene {
+	s := &replayScene{g: g, replay: r}
+	if r.Divergence > 0 {
+		s.turn = r.Divergence // 食い違ったターンから見せる
+	}
+	return s
+}
+
+// Update: Left/Right でターンを移動、Esc で終了画面へ戻る
+func (s *replayScene) Update() error {
+	last := len(s.replay.Solution) - 1
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight) && s.turn < last:
+		s.turn++
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft) && s.turn > 0:
+		s.turn--
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		s.g.setPhase(usecase.PhaseEnd)
+	}
+	return nil
+}
+
+// Draw: 左に勝ち筋、右に実際の入力。表示中のターンまでの入力と結果、そのターン終了時の行列と結果バー
+func (s *replayScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.Black)
+	r := s.replay
+	ui.DrawText(screen, fmt.Sprintf("Winning line vs your game - turn %d/%d", s.turn+1, len(r.Solution)), 10, 10)
+	if r.Divergence >= 0 {
+		ui.DrawText(screen, fmt.Sprintf("First divergence: turn %d", r.Divergence+1), 400, 10)
+	}
+	columns := []struct {
+		title string
+		steps []usecase.ReplayStep
+		x     int
+	}{
+		{"Winning line", r.Solution, 10},
+		{"Your inputs", r.Actual, 330},
+	}
+	for _, col := range columns {
+		ui.DrawText(screen, col.title, col.x, 40)
+		for turn := 0; turn <= s.turn && turn < len(col.steps); turn++ {
+			y := 60 + turn*18
+			if turn == r.Divergence {
+				drawRect(screen, float64(col.x-4), float64(y-1), 300, 16, color.RGBA{120, 100, 0, 255})
+			}
+			step := col.steps[turn]
+			ui.DrawText(screen, fmt.Sprintf("%2d: input %-8s result %s %s", turn+1, s.g.engine.Session().Granularity().FormatInput(step.Input), engine.FormatFloat(step.Result), engine.WinLose(step.Win)), col.x, y)
+		}
+		if s.turn < len(col.steps) {
+			step := col.steps[s.turn]
+			drawMatrix(screen, step.Player, col.x, 270, true)
+			drawMatrix(screen, step.Enemy, col.x+120, 270, false)
+			drawResultBarAt(screen, step.Result, col.x+70, 360, 200)
+		}
+	}
+	ui.DrawText(screen, "[Left/Right] Turn  [Esc] Back", 10, 460)
+}
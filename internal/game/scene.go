@@ -0,0 +1,108 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Scene is one screen of the game. Only the top scene of the stack is updated and drawn.
+type Scene interface {
+	Update() error
+	Draw(screen *ebiten.Image)
+}
+
+// fadeFrames: 暗転・明転それぞれにかけるフレーム数
+const fadeFrames = 8
+
+// sceneManager: シーンのスタック。Push / Pop / Replace は暗転しきった時点でまとめて適用する
+// フェード中はシーンの Update を呼ばない（切り替え途中の画面で入力を受け付けない）
+type sceneManager struct {
+	stack   []Scene
+	pending []func() // 暗転後に適用するスタック操作
+	alpha   float64  // 暗転の濃さ 0〜1
+	step    float64  // 1 フレームの alpha の変化量。0 ならフェードせず即座に切り替える
+}
+
+// newSceneManager: root をスタックの底に置く。root は Pop されない
+func newSceneManager(root Scene, frames int) *sceneManager {
+	m := &sceneManager{stack: []Scene{root}}
+	if frames > 0 {
+		m.step = 1 / float64(frames)
+	}
+	return m
+}
+
+// Push: s を一番上に積む
+func (m *sceneManager) Push(s Scene) {
+	m.request(func() { m.stack = append(m.stack, s) })
+}
+
+// Pop: 一番上のシーンを外す
+func (m *sceneManager) Pop() {
+	m.request(func() {
+		if len(m.stack) > 1 {
+			m.stack = m.stack[:len(m.stack)-1]
+		}
+	})
+}
+
+// Replace: 一番上のシーンを s に差し替える
+func (m *sceneManager) Replace(s Scene) {
+	m.request(func() { m.stack[len(m.stack)-1] = s })
+}
+
+// PopUntil: keep を満たすシーン（または底）が一番上になるまで外す
+func (m *sceneManager) PopUntil(keep func(Scene) bool) {
+	m.request(func() {
+		for len(m.stack) > 1 && !keep(m.stack[len(m.stack)-1]) {
+			m.stack = m.stack[:len(m.stack)-1]
+		}
+	})
+}
+
+func (m *sceneManager) request(op func()) {
+	if m.step == 0 {
+		op()
+		return
+	}
+	m.pending = append(m.pending, op)
+}
+
+// Top returns the scene currently shown. While fading out it is still the old scene.
+func (m *sceneManager) Top() Scene {
+	return m.stack[len(m.stack)-1]
+}
+
+// Update: フェード中なら進め、そうでなければ一番上のシーンを更新する
+func (m *sceneManager) Update() error {
+	if len(m.pending) > 0 {
+		m.alpha += m.step
+		if m.alpha >= 1 {
+			m.alpha = 1
+			ops := m.pending
+			m.pending = nil
+			for _, op := range ops {
+				op()
+			}
+		}
+		return nil
+	}
+	if m.alpha > 0 {
+		m.alpha -= m.step
+		if m.alpha < 0 {
+			m.alpha = 0
+		}
+		return nil
+	}
+	return m.Top().Update()
+}
+
+// Draw: 一番上のシーンを描き、フェード中は黒を重ねる
+func (m *sceneManager) Draw(screen *ebiten.Image) {
+	m.Top().Draw(screen)
+	if m.alpha > 0 {
+		w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+		drawRect(screen, 0, 0, float64(w), float64(h), color.RGBA{0, 0, 0, uint8(255 * m.alpha)})
+	}
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// fakeScene: Update の回数を数えるだけのシーン
+type fakeScene struct {
+	name    string
+	updates int
+}
+
+func (s *fakeScene) Update() error {
+	s.updates++
+	return nil
+}
+
+func (s *fakeScene) Draw(*ebiten.Image) {}
+
+// sceneOp: テストで sceneManager に要求するスタック操作
+type sceneOp struct {
+	kind  string // push, pop, replace, popUntil
+	scene string // push / replace するシーン、popUntil で残すシーン（空なら底まで）
+}
+
+func TestSceneManager(t *testing.T) {
+	push := func(name string) sceneOp { return sceneOp{"push", name} }
+	tests := []struct {
+		name        string
+		frames      int // フェードのフレーム数（0 なら即座に切り替える）
+		ops         []sceneOp
+		updates     int // 操作の後に呼ぶ Update の回数
+		wantStack   []string
+		wantAlpha   float64
+		wantUpdates int // 一番上のシーンが受けた Update の回数
+	}{
+		{"root only", 0, nil, 2, []string{"root"}, 0, 2},
+		{"push without fade", 0, []sceneOp{push("a")}, 1, []string{"root", "a"}, 0, 1},
+		{"pop", 0, []sceneOp{push("a"), push("b"), {"pop", ""}}, 0, []string{"root", "a"}, 0, 0},
+		{"pop keeps the root", 0, []sceneOp{{"pop", ""}}, 1, []string{"root"}, 0, 1},
+		{"replace", 0, []sceneOp{push("a"), {"replace", "b"}}, 0, []string{"root", "b"}, 0, 0},
+		{"replace the root", 0, []sceneOp{{"replace", "a"}}, 0, []string{"a"}, 0, 0},
+		{"pop until a scene", 0, []sceneOp{push("a"), push("b"), push("c"), {"popUntil", "a"}}, 0, []string{"root", "a"}, 0, 0},
+		{"pop until the root", 0, []sceneOp{push("a"), push("b"), {"popUntil", ""}}, 0, []string{"root"}, 0, 0},
+		// フェードアウト中は古いシーンのまま、Update も呼ばない
+		{"fading out", 4, []sceneOp{push("a")}, 3, []string{"root"}, 0.75, 0},
+		{"applied when dark", 4, []sceneOp{push("a")}, 4, []string{"root", "a"}, 1, 0},
+		{"fading in", 4, []sceneOp{push("a")}, 6, []string{"root", "a"}, 0.5, 0},
+		{"faded in", 4, []sceneOp{push("a")}, 8, []string{"root", "a"}, 0, 0},
+		// 1/3 ずつでは丸め誤差が残り、最後のフレームで 0 に揃える
+		{"fade-in clamps at zero", 3, []sceneOp{push("a")}, 7, []string{"root", "a"}, 0, 0},
+		{"updates after the fade", 4, []sceneOp{push("a")}, 10, []string{"root", "a"}, 0, 2},
+		{"queued operations apply together", 2, []sceneOp{push("a"), push("b"), {"replace", "c"}}, 2, []string{"root", "a", "c"}, 1, 0},
+		{"pop after a fade", 2, []sceneOp{push("a"), {"pop", ""}}, 4, []string{"root"}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenes := map[string]*fakeScene{}
+			scene := func(name string) *fakeScene {
+				if scenes[name] == nil {
+					scenes[name] = &fakeScene{name: name}
+				}
+				return scenes[name]
+			}
+			m := newSceneManager(scene("root"), tt.frames)
+			for _, op := range tt.ops {
+				switch op.kind {
+				case "push":
+					m.Push(scene(op.scene))
+				case "pop":
+					m.Pop()
+				case "replace":
+					m.Replace(scene(op.scene))
+				case "popUntil":
+					m.PopUntil(func(s Scene) bool { return s.(*fakeScene).name == op.scene })
+				}
+			}
+			for i := 0; i < tt.updates; i++ {
+				if err := m.Update(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var stack []string
+			for _, s := range m.stack {
+				stack = append(stack, s.(*fakeScene).name)
+			}
+			if !reflect.DeepEqual(stack, tt.wantStack) {
+				t.Errorf("stack = %v, want %v", stack, tt.wantStack)
+			}
+			if math.Abs(m.alpha-tt.wantAlpha) > 1e-9 {
+				t.Errorf("alpha = %v, want %v", m.alpha, tt.wantAlpha)
+			}
+			if got := m.Top().(*fakeScene).updates; got != tt.wantUpdates {
+				t.Errorf("top scene updated %d times, want %d", got, tt.wantUpdates)
+			}
+		})
+	}
+}
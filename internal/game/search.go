@@ -0,0 +1,116 @@
+package game
+
+import (
+	"axiom_shift/internal/engine"
+	"axiom_shift/internal/ui"
+	"axiom_shift/internal/usecase"
+	"context"
+	"fmt"
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// seedSearch: バックグラウンドで実行中の seed 探索（敗北後の勝ち筋の探索にも使う）
+// 結果は done チャネル経由で Update（メインゴルーチン）に受け渡す
+type seedSearch struct {
+	label    string
+	cancel   context.CancelFunc
+	done     chan seedSearchResult
+	onDone   func(seedSearchResult)
+	onCancel func() // Esc で呼ぶ。loading フェーズから出る遷移をする
+	mu       sync.Mutex
+	progress usecase.SeedProgress
+	reported bool // progress が一度でも通知されたか
+}
+
+// seedSearchResult: 探索ゴルーチンの結果
+type seedSearchResult struct {
+	report usecase.SeedReport
+	inputs []float64 // 勝ち筋の探索で見つかった入力列
+	ok     bool
+	err    error
+}
+
+// seedSearchFunc: 探索本体。進捗は progress で通知する
+type seedSearchFunc func(ctx context.Context, progress func(usecase.SeedProgress)) seedSearchResult
+
+// beginSearch: run を別ゴルーチンで実行して loading フェーズに入る。完了時はメインゴルーチンで onDone を呼ぶ
+// Esc でキャンセルするとメニューへ戻る
+func (g *Game) beginSearch(label string, run seedSearchFunc, onDone func(seedSearchResult)) {
+	g.beginTask(label, run, onDone, func() {
+		g.backToMenu("Seed search cancelled")
+	})
+}
+
+// beginTask: beginSearch と同じだが、Esc でキャンセルしたときの遷移を onCancel で決める
+func (g *Game) beginTask(label string, run seedSearchFunc, onDone func(seedSearchResult), onCancel func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &seedSearch{
+		label:    label,
+		cancel:   cancel,
+		done:     make(chan seedSearchResult, 1), // キャンセル後に結果を捨てても送信側が詰まらないようバッファ付き
+		onDone:   onDone,
+		onCancel: onCancel,
+	}
+	go func() {
+		s.done <- run(ctx, s.setProgress)
+	}()
+	g.search = s
+	g.setPhase(usecase.PhaseLoading)
+}
+
+func (s *seedSearch) setProgress(p usecase.SeedProgress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.progress = p
+	s.reported = true
+}
+
+// snapshot: 最新の進捗と、それが通知済みかどうか
+func (s *seedSearch) snapshot() (usecase.SeedProgress, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.progress, s.reported
+}
+
+// loadingScene: 探索の進捗表示。search は loading フェーズを出ると g.search から外れるので自分で持つ
+type loadingScene struct {
+	g      *Game
+	search *seedSearch
+}
+
+// Update: Esc でキャンセル、完了していれば結果を受け取る
+func (s *loadingScene) Update() error {
+	g := s.g
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.search.onCancel() // 探索は loading を出るフックで止まる
+		return nil
+	}
+	select {
+	case r := <-s.search.done:
+		g.search = nil
+		s.search.cancel()
+		s.search.onDone(r)
+	default:
+	}
+	return nil
+}
+
+// Draw: 探索の進捗を表示する
+func (s *loadingScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.Black)
+	ui.DrawText(screen, s.search.label+"...", 10, 10)
+	ui.DrawText(screen, "[Esc] Cancel", 10, 460)
+	p, ok := s.search.snapshot()
+	if !ok { // 進捗を通知しない探索（勝ち筋の探索など）はラベルだけ
+		return
+	}
+	ui.DrawText(screen, fmt.Sprintf("Candidates tried: %d  (random playouts: %d)", p.Tried, p.Simulations), 10, 40)
+	ui.DrawText(screen, fmt.Sprintf("Rejected  rough: %d  deep: %d  proof: %d  constraint: %d  quality: %d", p.RoughRejected, p.DeepRejected, p.ProofRejected, p.ConstraintRejected, p.QualityRejected), 10, 60)
+	if p.HasBest {
+		ui.DrawText(screen, fmt.Sprintf("Best so far: seed %d (win rate %s)", p.BestSeed, engine.FormatFloat(p.BestWinRate)), 10, 80)
+	}
+}
@@ -0,0 +1,32 @@
+package logic
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// Named streams for the subsystems that consume randomness from a root seed.
+const (
+	StreamRule        = "rule"
+	StreamEnemyAI     = "enemy-ai"
+	StreamSeedSearch  = "seed-search"
+	StreamSeedQuality = "seed-quality"
+)
+
+// DeriveSeed deterministically derives a child seed for the named stream.
+// The value depends only on root and name (FNV-1a over both), so adding a new
+// stream never changes the values observed by existing ones.
+func DeriveSeed(root int64, name string) int64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(root))
+	h.Write(buf[:])
+	h.Write([]byte(name))
+	return int64(h.Sum64() &^ (1 << 63))
+}
+
+// Derive returns an independent SeedManager for the named child stream.
+// It neither reads nor advances the parent's random state.
+func (sm *SeedManager) Derive(name string) *SeedManager {
+	return NewSeedManagerWithFixedValue(DeriveSeed(sm.seed, name))
+}
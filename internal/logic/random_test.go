@@ -0,0 +1,61 @@
+package logic
+
+import "testing"
+
+func TestSeedManager_RandomSourceDeterminism(t *testing.T) {
+	tests := []struct {
+		name  string
+		seed1 int64
+		seed2 int64
+		equal bool
+	}{
+		{"same seed same sequence", 42, 42, true},
+		{"different seed different sequence", 42, 43, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a, b RandomSource = NewSeedManagerWithFixedValue(tt.seed1), NewSeedManagerWithFixedValue(tt.seed2)
+			equal := a.Int63() == b.Int63() && a.Intn(1000) == b.Intn(1000) && a.Float64() == b.Float64()
+			sa := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+			sb := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+			a.Shuffle(len(sa), func(i, j int) { sa[i], sa[j] = sa[j], sa[i] })
+			b.Shuffle(len(sb), func(i, j int) { sb[i], sb[j] = sb[j], sb[i] })
+			for i := range sa {
+				if sa[i] != sb[i] {
+					equal = false
+				}
+			}
+			if equal != tt.equal {
+				t.Errorf("RandomSource determinism: got %v, want %v", equal, tt.equal)
+			}
+		})
+	}
+}
+
+func TestSeedManager_RandomSourceRanges(t *testing.T) {
+	tests := []struct {
+		name string
+		seed int64
+		n    int
+	}{
+		{"n=1", 1, 1},
+		{"n=10", 2, 10},
+		{"n=1000", 3, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := NewSeedManagerWithFixedValue(tt.seed)
+			for i := 0; i < 100; i++ {
+				if v := sm.Intn(tt.n); v < 0 || v >= tt.n {
+					t.Fatalf("Intn(%d) out of range: %d", tt.n, v)
+				}
+				if f := sm.Float64(); f < 0 || f >= 1 {
+					t.Fatalf("Float64 out of range: %v", f)
+				}
+				if v := sm.Int63(); v < 0 {
+					t.Fatalf("Int63 negative: %d", v)
+				}
+			}
+		})
+	}
+}
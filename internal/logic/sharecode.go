@@ -0,0 +1,175 @@
+package logic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+// ShareCodeVersion is the format version written into new share codes.
+const ShareCodeVersion = 1
+
+// GeneratorUniform identifies the uniform [-1, 1) rule generator.
+const GeneratorUniform = 0
+
+// crockfordAlphabet is Crockford's base32 alphabet (no I, L, O, U).
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const (
+	shareCodePayloadLen = 13                      // version, size, battleMax, difficulty, generator, seed(8)
+	shareCodeBytesLen   = shareCodePayloadLen + 2 // + checksum(2)
+	shareCodeCharsLen   = shareCodeBytesLen * 8 / 5
+	shareCodeGroupLen   = 4
+)
+
+// Errors returned by ShareCode.Encode, ShareCode.Validate, ParseShareCode and ParseSeedInput.
+var (
+	ErrShareCodeLength   = errors.New("share code has wrong length")
+	ErrShareCodeChar     = errors.New("share code contains an invalid character")
+	ErrShareCodeChecksum = errors.New("share code checksum mismatch")
+	ErrShareCodeVersion  = errors.New("unsupported share code version")
+	ErrShareCodeRange    = errors.New("share code field out of range")
+	ErrSeedInputEmpty    = errors.New("seed input is empty")
+	ErrShareCodeSetup    = errors.New("share code setup not supported")
+)
+
+// ShareCode is a complete game setup that players can exchange as text.
+type ShareCode struct {
+	Seed       int64
+	Size       int
+	BattleMax  int
+	Difficulty int
+	Generator  int
+}
+
+// Encode returns the code as grouped Crockford base32, e.g. "1A2B-3C4D-...".
+func (c ShareCode) Encode() (string, error) {
+	for _, v := range []int{c.Size, c.BattleMax, c.Difficulty, c.Generator} {
+		if v < 0 || v > 255 {
+			return "", fmt.Errorf("%w: %d", ErrShareCodeRange, v)
+		}
+	}
+	buf := make([]byte, shareCodeBytesLen)
+	buf[0] = ShareCodeVersion
+	buf[1] = byte(c.Size)
+	buf[2] = byte(c.BattleMax)
+	buf[3] = byte(c.Difficulty)
+	buf[4] = byte(c.Generator)
+	binary.BigEndian.PutUint64(buf[5:13], uint64(c.Seed))
+	binary.BigEndian.PutUint16(buf[13:], shareCodeChecksum(buf[:shareCodePayloadLen]))
+
+	var sb strings.Builder
+	for i, ch := range encodeBase32(buf) {
+		if i > 0 && i%shareCodeGroupLen == 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(ch)
+	}
+	return sb.String(), nil
+}
+
+// ParseShareCode decodes a share code typed by a player.
+// Case, spaces and dashes are ignored, and the easily confused letters
+// O, I and L are read as 0, 1 and 1.
+func ParseShareCode(s string) (ShareCode, error) {
+	chars := make([]byte, 0, shareCodeCharsLen)
+	for _, r := range strings.ToUpper(s) {
+		switch r {
+		case '-', ' ':
+			continue
+		case 'O':
+			r = '0'
+		case 'I', 'L':
+			r = '1'
+		}
+		if r > 0x7f || strings.IndexRune(crockfordAlphabet, r) < 0 {
+			return ShareCode{}, fmt.Errorf("%w: %q", ErrShareCodeChar, r)
+		}
+		chars = append(chars, byte(r))
+	}
+	if len(chars) != shareCodeCharsLen {
+		return ShareCode{}, fmt.Errorf("%w: got %d characters, want %d", ErrShareCodeLength, len(chars), shareCodeCharsLen)
+	}
+	buf := decodeBase32(chars)
+	if binary.BigEndian.Uint16(buf[13:]) != shareCodeChecksum(buf[:shareCodePayloadLen]) {
+		return ShareCode{}, ErrShareCodeChecksum
+	}
+	if buf[0] != ShareCodeVersion {
+		return ShareCode{}, fmt.Errorf("%w: %d", ErrShareCodeVersion, buf[0])
+	}
+	return ShareCode{
+		Size:       int(buf[1]),
+		BattleMax:  int(buf[2]),
+		Difficulty: int(buf[3]),
+		Generator:  int(buf[4]),
+		Seed:       int64(binary.BigEndian.Uint64(buf[5:13])),
+	}, nil
+}
+
+func shareCodeChecksum(payload []byte) uint16 {
+	return uint16(crc32.ChecksumIEEE(payload))
+}
+
+// encodeBase32 packs data (a multiple of 5 bytes) into base32 characters.
+func encodeBase32(data []byte) []byte {
+	out := make([]byte, 0, len(data)*8/5)
+	var acc uint64
+	bits := 0
+	for _, b := range data {
+		acc = acc<<8 | uint64(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out = append(out, crockfordAlphabet[(acc>>bits)&0x1f])
+		}
+	}
+	return out
+}
+
+// decodeBase32 is the inverse of encodeBase32 for validated characters.
+func decodeBase32(chars []byte) []byte {
+	out := make([]byte, 0, len(chars)*5/8)
+	var acc uint64
+	bits := 0
+	for _, c := range chars {
+		acc = acc<<5 | uint64(strings.IndexByte(crockfordAlphabet, c))
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			out = append(out, byte(acc>>bits))
+		}
+	}
+	return out
+}
+
+// ParseSeedInput accepts either a decimal seed or a share code.
+// A bare seed keeps every other setting from defaults.
+func ParseSeedInput(s string, defaults ShareCode) (ShareCode, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ShareCode{}, ErrSeedInputEmpty
+	}
+	if seed, err := strconv.ParseInt(s, 10, 64); err == nil {
+		c := defaults
+		c.Seed = seed
+		return c, nil
+	}
+	return ParseShareCode(s)
+}
+
+// Validate reports whether the code describes a setup this build can play
+// with size x size matrices.
+func (c ShareCode) Validate(size int) error {
+	switch {
+	case c.Size != size:
+		return fmt.Errorf("%w: matrix size %d (want %d)", ErrShareCodeSetup, c.Size, size)
+	case c.BattleMax <= 0:
+		return fmt.Errorf("%w: battle count %d", ErrShareCodeSetup, c.BattleMax)
+	case c.Generator != GeneratorUniform:
+		return fmt.Errorf("%w: generator %d", ErrShareCodeSetup, c.Generator)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package logic
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestShareCode_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		code ShareCode
+	}{
+		{"default game", ShareCode{Seed: 1234567890123456789, Size: 3, BattleMax: 10, Generator: GeneratorUniform}},
+		{"zero seed", ShareCode{Seed: 0, Size: 2, BattleMax: 5}},
+		{"negative seed", ShareCode{Seed: -42, Size: 4, BattleMax: 255, Difficulty: 3, Generator: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.code.Encode()
+			if err != nil {
+				t.Fatalf("Encode error: %v", err)
+			}
+			if len(s) != 29 || strings.Count(s, "-") != 5 {
+				t.Errorf("Encode format: got %q", s)
+			}
+			got, err := ParseShareCode(s)
+			if err != nil {
+				t.Fatalf("ParseShareCode(%q) error: %v", s, err)
+			}
+			if got != tt.code {
+				t.Errorf("round trip: got %+v, want %+v", got, tt.code)
+			}
+		})
+	}
+}
+
+func TestShareCode_EncodeRange(t *testing.T) {
+	tests := []struct {
+		name string
+		code ShareCode
+	}{
+		{"size too large", ShareCode{Size: 256, BattleMax: 10}},
+		{"negative battleMax", ShareCode{Size: 3, BattleMax: -1}},
+		{"difficulty too large", ShareCode{Size: 3, BattleMax: 10, Difficulty: 300}},
+		{"negative generator", ShareCode{Size: 3, BattleMax: 10, Generator: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.code.Encode(); !errors.Is(err, ErrShareCodeRange) {
+				t.Errorf("Encode error = %v, want %v", err, ErrShareCodeRange)
+			}
+		})
+	}
+}
+
+func TestParseShareCode_Tolerance(t *testing.T) {
+	want := ShareCode{Seed: 987654321, Size: 3, BattleMax: 10}
+	s, err := want.Encode()
+	if err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	ambiguous := strings.NewReplacer("0", "o", "1", "l").Replace(s)
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"canonical", s},
+		{"lower case", strings.ToLower(s)},
+		{"no dashes", strings.ReplaceAll(s, "-", "")},
+		{"spaces", strings.ReplaceAll(s, "-", " ")},
+		{"confusable letters", ambiguous},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseShareCode(tt.input)
+			if err != nil {
+				t.Fatalf("ParseShareCode(%q) error: %v", tt.input, err)
+			}
+			if got != want {
+				t.Errorf("ParseShareCode(%q) = %+v, want %+v", tt.input, got, want)
+			}
+		})
+	}
+}
+
+func TestParseShareCode_Errors(t *testing.T) {
+	valid, _ := ShareCode{Seed: 5, Size: 3, BattleMax: 10}.Encode()
+	chars := []byte(strings.ReplaceAll(valid, "-", ""))
+	typo := append([]byte(nil), chars...)
+	if typo[10] == 'Z' {
+		typo[10] = 'Y'
+	} else {
+		typo[10] = 'Z'
+	}
+	wrongVersion := make([]byte, shareCodeBytesLen)
+	wrongVersion[0] = ShareCodeVersion + 1
+	sum := shareCodeChecksum(wrongVersion[:shareCodePayloadLen])
+	wrongVersion[13], wrongVersion[14] = byte(sum>>8), byte(sum)
+
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"empty", "", ErrShareCodeLength},
+		{"too short", valid[:10], ErrShareCodeLength},
+		{"invalid char", strings.Replace(valid, valid[:1], "U", 1), ErrShareCodeChar},
+		{"non ascii", "あ" + valid, ErrShareCodeChar},
+		{"typo", string(typo), ErrShareCodeChecksum},
+		{"unknown version", string(encodeBase32(wrongVersion)), ErrShareCodeVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseShareCode(tt.input); !errors.Is(err, tt.want) {
+				t.Errorf("ParseShareCode(%q) error = %v, want %v", tt.input, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSeedInput(t *testing.T) {
+	defaults := ShareCode{Size: 3, BattleMax: 10}
+	code := ShareCode{Seed: 77, Size: 3, BattleMax: 5, Difficulty: 1}
+	encoded, _ := code.Encode()
+	tests := []struct {
+		name    string
+		input   string
+		want    ShareCode
+		wantErr error
+	}{
+		{"decimal seed", "123456", ShareCode{Seed: 123456, Size: 3, BattleMax: 10}, nil},
+		{"negative seed with spaces", "  -9 ", ShareCode{Seed: -9, Size: 3, BattleMax: 10}, nil},
+		{"share code", encoded, code, nil},
+		{"empty", "   ", ShareCode{}, ErrSeedInputEmpty},
+		{"garbage", "hello!", ShareCode{}, ErrShareCodeChar},
+		{"seed overflow falls back to share code", "99999999999999999999", ShareCode{}, ErrShareCodeLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSeedInput(tt.input, defaults)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseSeedInput(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSeedInput(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShareCode_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    ShareCode
+		size    int
+		wantErr bool
+	}{
+		{"valid", ShareCode{Seed: 1, Size: 3, BattleMax: 10}, 3, false},
+		{"size mismatch", ShareCode{Seed: 1, Size: 2, BattleMax: 10}, 3, true},
+		{"zero battles", ShareCode{Seed: 1, Size: 3, BattleMax: 0}, 3, true},
+		{"unknown generator", ShareCode{Seed: 1, Size: 3, BattleMax: 10, Generator: 9}, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.code.Validate(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrShareCodeSetup) {
+				t.Errorf("Validate error = %v, want %v", err, ErrShareCodeSetup)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package tui is the terminal front end of the engine. It decodes raw key bytes into the same
+// actions as the Ebiten front end and renders each ViewModel as ANSI-colored text.
+package tui
+
+import "axiom_shift/internal/usecase"
+
+// Event is one key press; each event is one frame of the engine.
+type Event struct {
+	Actions []usecase.Action
+	Text    string // 入力欄に打つ文字（数字と '.'）
+	Quit    bool
+}
+
+const (
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyBackspace = 0x08
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
+
+// Decode splits raw terminal input into key events. Keys mirror the Ebiten front end:
+// 0-9 input, Enter confirms, Backspace or a lone Esc cancels, R retries; Q, Ctrl-C or Ctrl-D quits.
+// Escape sequences of other keys (arrows, function keys) are skipped.
+func Decode(b []byte) []Event {
+	var events []Event
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c >= '0' && c <= '9':
+			events = append(events, Event{Actions: []usecase.Action{usecase.DigitAction(int(c - '0'))}, Text: string(c)})
+		case c == '.':
+			events = append(events, Event{Text: "."})
+		case c == '\r' || c == '\n':
+			if c == '\r' && i+1 < len(b) && b[i+1] == '\n' {
+				i++ // CRLF は 1 回の Enter
+			}
+			events = append(events, Event{Actions: []usecase.Action{usecase.ConfirmAction}})
+		case c == keyBackspace || c == keyDelete:
+			events = append(events, Event{Actions: []usecase.Action{usecase.CancelAction}})
+		case c == keyEscape:
+			if n := escapeLen(b[i:]); n > 1 {
+				i += n - 1
+				continue
+			}
+			events = append(events, Event{Actions: []usecase.Action{usecase.CancelAction}})
+		case c == 'r' || c == 'R':
+			events = append(events, Event{Actions: []usecase.Action{usecase.RetryAction}})
+		case c == 'q' || c == 'Q' || c == keyCtrlC || c == keyCtrlD:
+			events = append(events, Event{Quit: true})
+		}
+	}
+	return events
+}
+
+// escapeLen: b の先頭（ESC）から始まるエスケープシーケンスの長さ。ESC 単独なら 1
+func escapeLen(b []byte) int {
+	if len(b) < 2 {
+		return 1
+	}
+	switch b[1] {
+	case '[': // CSI: 0x40-0x7e の終端バイトまで
+		for j := 2; j < len(b); j++ {
+			if b[j] >= 0x40 && b[j] <= 0x7e {
+				return j + 1
+			}
+		}
+		return len(b)
+	case 'O': // SS3: 次の 1 バイトまで
+		if len(b) < 3 {
+			return len(b)
+		}
+		return 3
+	}
+	return 1
+}
@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"axiom_shift/internal/usecase"
+)
+
+func TestDecode(t *testing.T) {
+	digit := func(n int, text string) Event {
+		return Event{Actions: []usecase.Action{usecase.DigitAction(n)}, Text: text}
+	}
+	confirm := Event{Actions: []usecase.Action{usecase.ConfirmAction}}
+	cancel := Event{Actions: []usecase.Action{usecase.CancelAction}}
+	tests := []struct {
+		name string
+		in   string
+		want []Event
+	}{
+		{"empty", "", nil},
+		{"digits", "07", []Event{digit(0, "0"), digit(7, "7")}},
+		{"decimal point", "0.5", []Event{digit(0, "0"), {Text: "."}, digit(5, "5")}},
+		{"enter", "\r", []Event{confirm}},
+		{"newline", "\n", []Event{confirm}},
+		{"crlf is one enter", "\r\n", []Event{confirm}},
+		{"two enters", "\r\r", []Event{confirm, confirm}},
+		{"backspace", "\x7f\x08", []Event{cancel, cancel}},
+		{"lone escape", "\x1b", []Event{cancel}},
+		{"escape then key", "\x1b5", []Event{cancel, digit(5, "5")}},
+		{"arrow skipped", "\x1b[A1", []Event{digit(1, "1")}},
+		{"csi with parameters skipped", "\x1b[1;5C2", []Event{digit(2, "2")}},
+		{"unfinished csi", "\x1b[1", nil},
+		{"ss3 skipped", "\x1bOP3", []Event{digit(3, "3")}},
+		{"unfinished ss3", "\x1bO", nil},
+		{"retry", "rR", []Event{{Actions: []usecase.Action{usecase.RetryAction}}, {Actions: []usecase.Action{usecase.RetryAction}}}},
+		{"quit keys", "qQ\x03\x04", []Event{{Quit: true}, {Quit: true}, {Quit: true}, {Quit: true}}},
+		{"other keys ignored", "ab ,", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Decode([]byte(tt.in)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"axiom_shift/internal/engine"
+	"fmt"
+	"strings"
+)
+
+const (
+	logLines = 12 // 表示するログの行数（新しい順に残す）
+	barHalf  = 20 // 結果バーの中央から片側の幅
+	reset    = "\x1b[0m"
+	clear    = "\x1b[H\x1b[2J" // カーソルを左上へ移して画面を消す
+)
+
+// 256 色パレットの背景色
+const (
+	bgGray  = 240
+	bgGreen = 34
+	bgRed   = 160
+)
+
+// Render draws one frame as ANSI text, starting with a screen clear.
+// Lines end with CRLF because raw mode turns off the terminal's newline translation.
+func Render(vm engine.ViewModel) string {
+	var b strings.Builder
+	b.WriteString(clear)
+	line := func(format string, a ...any) {
+		fmt.Fprintf(&b, format, a...)
+		b.WriteString("\r\n")
+	}
+	line("AXIOM SHIFT  Seed: %d  Code: %s", vm.Seed, vm.ShareCode)
+	line("Battle %d/%d  Won: %d", vm.Battle, vm.BattleMax, vm.Wins)
+	line("")
+	log := vm.Log
+	if len(log) > logLines {
+		log = log[len(log)-logLines:]
+	}
+	for _, l := range log {
+		line("%s", l)
+	}
+	line("")
+	if vm.Player != nil {
+		// 左にプレイヤー（青）、右に敵（赤）
+		width := 2 * len(vm.Player[0])
+		line("%-*s    %s", width, "Player", "Enemy")
+		for i := range vm.Player {
+			line("%s    %s", matrixRow(vm.Player[i], true), matrixRow(vm.Enemy[i], false))
+		}
+		line("")
+	}
+	if vm.HasResult {
+		line("Result %s %s", resultBar(vm.Result), engine.FormatFloat(vm.Result))
+	}
+	if vm.Message != "" {
+		line("%s", vm.Message)
+	}
+	line("%s", vm.Prompt)
+	line("[Q] Quit")
+	return b.String()
+}
+
+// matrixRow: 行列の 1 行を色の濃さのセルで描く（Ebiten 版と同じく 0-1 に切り詰める）
+func matrixRow(row []float64, player bool) string {
+	var b strings.Builder
+	for _, v := range row {
+		b.WriteString(bg(cellColor(v, player)) + "  " + reset)
+	}
+	return b.String()
+}
+
+// cellColor: 0-1 の値を 5 段階の濃さにした 256 色パレットの番号。プレイヤーは青、敵は赤
+func cellColor(v float64, player bool) int {
+	v = clamp(v, 0, 1)
+	level := 1 + int(v*4+0.5) // 1-5: 値 0 でも黒にはしない
+	if player {
+		return 16 + level
+	}
+	return 16 + 36*level
+}
+
+// resultBar: 中央から右（緑）がプレイヤー有利、左（赤）が敵有利
+func resultBar(result float64) string {
+	n := int(float64(barHalf) * clamp(result, -1, 1))
+	left, right := bg(bgGray)+strings.Repeat(" ", barHalf), bg(bgGray)+strings.Repeat(" ", barHalf)
+	if n < 0 {
+		left = bg(bgGray) + strings.Repeat(" ", barHalf+n) + bg(bgRed) + strings.Repeat(" ", -n)
+	} else if n > 0 {
+		right = bg(bgGreen) + strings.Repeat(" ", n) + bg(bgGray) + strings.Repeat(" ", barHalf-n)
+	}
+	return left + reset + "|" + right + reset
+}
+
+func bg(color int) string {
+	return fmt.Sprintf("\x1b[48;5;%dm", color)
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"axiom_shift/internal/engine"
+	"axiom_shift/internal/usecase"
+)
+
+func TestRender(t *testing.T) {
+	var longLog []string
+	for i := 1; i <= 15; i++ {
+		longLog = append(longLog, fmt.Sprintf("line %d", i))
+	}
+	game := engine.ViewModel{
+		Phase:     usecase.PhaseInput,
+		Log:       []string{"Battle 1: Input=3"},
+		Player:    [][]float64{{1, 0}, {0, 1}},
+		Enemy:     [][]float64{{0, 2}, {2, 0}},
+		Result:    0.5,
+		HasResult: true,
+		Prompt:    "Press 0-9 to input",
+		Seed:      42,
+		ShareCode: "ABCD",
+		Battle:    1,
+		BattleMax: 10,
+		Wins:      1,
+	}
+	withMessage := game
+	withMessage.Message = "input out of range"
+	long := game
+	long.Log = longLog
+	tests := []struct {
+		name    string
+		vm      engine.ViewModel
+		want    []string
+		notWant []string
+	}{
+		{"game", game, []string{
+			clear, "Seed: 42  Code: ABCD\r\n", "Battle 1/10  Won: 1\r\n", "Battle 1: Input=3\r\n",
+			"Player    Enemy\r\n", bg(21) + "  " + reset, bg(16+36) + "  " + reset, "Result ", " 0.50\r\n",
+			"Press 0-9 to input\r\n", "[Q] Quit\r\n",
+		}, []string{"input out of range"}},
+		{"message", withMessage, []string{"input out of range\r\n"}, nil},
+		{"keeps the newest log lines", long, []string{"line 4\r\n", "line 15\r\n"}, []string{"line 3\r\n"}},
+		{"before a game", engine.ViewModel{Phase: usecase.PhaseMenu}, []string{"Battle 0/0"}, []string{"Player", "Result"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.vm)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("Render missing %q in %q", w, got)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("Render should not contain %q: %q", w, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCellColor(t *testing.T) {
+	tests := []struct {
+		v      float64
+		player bool
+		want   int
+	}{
+		{-1, true, 17},
+		{0, true, 17},
+		{0.5, true, 19},
+		{1, true, 21},
+		{2, true, 21},
+		{0, false, 52},
+		{1, false, 196},
+	}
+	for _, tt := range tests {
+		if got := cellColor(tt.v, tt.player); got != tt.want {
+			t.Errorf("cellColor(%v, %v) = %d, want %d", tt.v, tt.player, got, tt.want)
+		}
+	}
+}
+
+func TestResultBar(t *testing.T) {
+	gray := func(n int) string { return bg(bgGray) + strings.Repeat(" ", n) }
+	tests := []struct {
+		result float64
+		want   string
+	}{
+		{0, gray(barHalf) + reset + "|" + gray(barHalf) + reset},
+		{0.5, gray(barHalf) + reset + "|" + bg(bgGreen) + strings.Repeat(" ", 10) + gray(10) + reset},
+		{3, gray(barHalf) + reset + "|" + bg(bgGreen) + strings.Repeat(" ", barHalf) + gray(0) + reset},
+		{-0.25, gray(15) + bg(bgRed) + strings.Repeat(" ", 5) + reset + "|" + gray(barHalf) + reset},
+		{-2, gray(0) + bg(bgRed) + strings.Repeat(" ", barHalf) + reset + "|" + gray(barHalf) + reset},
+	}
+	for _, tt := range tests {
+		if got := resultBar(tt.result); got != tt.want {
+			t.Errorf("resultBar(%v) = %q, want %q", tt.result, got, tt.want)
+		}
+	}
+}
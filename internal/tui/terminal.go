@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"axiom_shift/internal/engine"
+	"axiom_shift/internal/usecase"
+	"context"
+	"errors"
+	"io"
+)
+
+// Terminal runs an engine from terminal key presses and redraws it after each read.
+type Terminal struct {
+	engine *engine.Engine
+	keys   *keyInput
+	out    io.Writer
+}
+
+// New returns a terminal front end drawing to out. Start a game on Engine() before Run.
+func New(out io.Writer) *Terminal {
+	keys := &keyInput{}
+	return &Terminal{engine: engine.New(keys), keys: keys, out: out}
+}
+
+// Engine returns the engine driven by the terminal.
+func (t *Terminal) Engine() *engine.Engine {
+	return t.engine
+}
+
+// Run reads keys from in until the player quits, in ends or ctx is cancelled.
+func (t *Terminal) Run(ctx context.Context, in io.Reader) error {
+	chunks, errc := readChunks(ctx, in)
+	if err := t.draw(); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case b, ok := <-chunks:
+			if !ok {
+				if err := <-errc; !errors.Is(err, io.EOF) {
+					return err
+				}
+				return nil
+			}
+			more := true
+			for _, ev := range Decode(b) {
+				var err error
+				if more, err = t.Press(ev); err != nil {
+					return err
+				}
+				if !more {
+					break
+				}
+			}
+			// 終了キーの前までの操作も描いてから抜ける
+			if err := t.draw(); err != nil || !more {
+				return err
+			}
+		}
+	}
+}
+
+// Press processes one key as one engine frame and reports whether to keep running.
+// A battle is fought right after it is confirmed instead of waiting for the next key.
+func (t *Terminal) Press(ev Event) (bool, error) {
+	if ev.Quit {
+		return false, nil
+	}
+	e := t.engine
+	if s := e.Session(); s != nil && e.Phases().Current() == usecase.PhaseInput && s.Granularity() != usecase.InputDigits {
+		e.TypeEntry(ev.Text)
+	}
+	t.keys.actions = ev.Actions
+	err := e.Step()
+	for err == nil && e.Phases().Current() == usecase.PhaseBattle {
+		err = e.Step()
+	}
+	return err == nil, err
+}
+
+func (t *Terminal) draw() error {
+	_, err := io.WriteString(t.out, Render(t.engine.View()))
+	return err
+}
+
+// keyInput: Press で渡された操作を 1 フレームだけ返す
+type keyInput struct {
+	actions []usecase.Action
+}
+
+func (k *keyInput) Actions() []usecase.Action {
+	actions := k.actions
+	k.actions = nil
+	return actions
+}
+
+// readChunks: in を別 goroutine で読み、読めた分ずつ送る。読み終えたら最後のエラーを errc に入れて閉じる
+func readChunks(ctx context.Context, in io.Reader) (<-chan []byte, <-chan error) {
+	chunks := make(chan []byte)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(chunks)
+		buf := make([]byte, 256)
+		for {
+			n, err := in.Read(buf)
+			if n > 0 {
+				select {
+				case chunks <- append([]byte(nil), buf[:n]...):
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+	return chunks, errc
+}
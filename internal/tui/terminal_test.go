@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"axiom_shift/internal/usecase"
+)
+
+// startTerminal: 標準設定・FallbackSeed のゲームを始めた端末
+func startTerminal(t *testing.T, out io.Writer, granularity usecase.InputGranularity) *Terminal {
+	t.Helper()
+	term := New(out)
+	config := usecase.DefaultGameConfig()
+	player, enemy := config.NewCombatants()
+	if err := term.Engine().Start(usecase.FallbackSeed, "CODE", config.BattleMax, player, enemy, granularity); err != nil {
+		t.Fatal(err)
+	}
+	return term
+}
+
+func TestTerminal_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		granularity usecase.InputGranularity
+		in          string
+		wantInputs  []float64
+		wantPhase   usecase.Phase
+	}{
+		{"full game", usecase.InputDigits, "3\r7\r0\r1\r2\r3\r4\r5\r6\r9\r", []float64{3, 7, 0, 1, 2, 3, 4, 5, 6, 9}, usecase.PhaseEnd},
+		{"re-input", usecase.InputDigits, "3\x7f5\r", []float64{5}, usecase.PhaseInput},
+		{"quit stops reading", usecase.InputDigits, "3\rq4\r", []float64{3}, usecase.PhaseInput},
+		{"retry after the game", usecase.InputDigits, "0\r0\r0\r0\r0\r0\r0\r0\r0\r0\rr1\r", []float64{1}, usecase.PhaseInput},
+		{"typed entry", usecase.InputContinuous, "0.45\x7f\r\r", []float64{0.4 * 9}, usecase.PhaseInput},
+		{"unconfirmed", usecase.InputDigits, "8", nil, usecase.PhaseConfirm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			term := startTerminal(t, &out, tt.granularity)
+			if err := term.Run(context.Background(), strings.NewReader(tt.in)); err != nil {
+				t.Fatal(err)
+			}
+			s := term.Engine().Session()
+			if got := s.Inputs(); len(got) != len(tt.wantInputs) {
+				t.Fatalf("Inputs = %v, want %d inputs", got, len(tt.wantInputs))
+			}
+			// 10 キーと同じ値で比べるため 9 倍して丸める（連続値は 0.4 → 3.6）
+			for i, x := range s.Inputs() {
+				if d := x*9 - tt.wantInputs[i]; d > 1e-9 || d < -1e-9 {
+					t.Errorf("input %d = %v, want %v/9", i, x, tt.wantInputs[i])
+				}
+			}
+			if got := term.Engine().Phases().Current(); got != tt.wantPhase {
+				t.Errorf("phase %s, want %s", got, tt.wantPhase)
+			}
+			// 端末で遊んだ結果は同じ seed・入力の Replay と一致する
+			player, enemy := usecase.DefaultGameConfig().NewCombatants()
+			steps := usecase.Replay(usecase.FallbackSeed, player, enemy, s.Inputs())
+			result := s.Result()
+			if len(result.Turns) != len(steps) {
+				t.Fatalf("%d turns, replay %d", len(result.Turns), len(steps))
+			}
+			for i, step := range steps {
+				if result.Turns[i].Result != step.Result || result.Turns[i].Win != step.Win {
+					t.Errorf("turn %d = %+v, replay %+v", i+1, result.Turns[i], step)
+				}
+			}
+			if result.Over && result.Win != steps[len(steps)-1].Win {
+				t.Errorf("Win = %v, replay %v", result.Win, steps[len(steps)-1].Win)
+			}
+			if !strings.HasPrefix(out.String(), clear) {
+				t.Errorf("output does not start with a frame: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestTerminal_RunErrors(t *testing.T) {
+	readErr := errors.New("read failed")
+	writeErr := errors.New("write failed")
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		in   io.Reader
+		out  io.Writer
+		want error
+	}{
+		{"read error", context.Background(), iotest.ErrReader(readErr), io.Discard, readErr},
+		{"cancelled", cancelled, blockingReader{}, io.Discard, context.Canceled},
+		{"first frame not written", context.Background(), strings.NewReader("1"), &errWriter{writeErr, 0}, writeErr},
+		{"frame after a key not written", context.Background(), strings.NewReader("1"), &errWriter{writeErr, 1}, writeErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := startTerminal(t, tt.out, usecase.InputDigits)
+			if err := term.Run(tt.ctx, tt.in); !errors.Is(err, tt.want) {
+				t.Errorf("Run = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminal_Press(t *testing.T) {
+	tests := []struct {
+		name      string
+		started   bool
+		ev        Event
+		wantMore  bool
+		wantPhase usecase.Phase
+	}{
+		{"text before start", false, Event{Text: "1"}, true, usecase.PhaseMenu},
+		{"quit before start", false, Event{Quit: true}, false, usecase.PhaseMenu},
+		{"digit", true, Event{Actions: []usecase.Action{usecase.DigitAction(1)}}, true, usecase.PhaseConfirm},
+		{"confirm without input", true, Event{Actions: []usecase.Action{usecase.ConfirmAction}}, true, usecase.PhaseInput},
+		{"quit", true, Event{Quit: true}, false, usecase.PhaseInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := New(io.Discard)
+			if tt.started {
+				term = startTerminal(t, io.Discard, usecase.InputDigits)
+			}
+			if more, err := term.Press(tt.ev); more != tt.wantMore || err != nil {
+				t.Errorf("Press = %v, %v, want %v, nil", more, err, tt.wantMore)
+			}
+			if got := term.Engine().Phases().Current(); got != tt.wantPhase {
+				t.Errorf("phase %s, want %s", got, tt.wantPhase)
+			}
+		})
+	}
+}
+
+// blockingReader: 何も返さず待ち続ける入力
+type blockingReader struct{}
+
+func (blockingReader) Read([]byte) (int, error) {
+	select {}
+}
+
+// errWriter: ok 回だけ書き込めて、その後は err を返す
+type errWriter struct {
+	err error
+	ok  int
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if w.ok == 0 {
+		return 0, w.err
+	}
+	w.ok--
+	return len(p), nil
+}
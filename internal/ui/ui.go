@@ -7,39 +7,39 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 )
 
-type UI struct {
-	battleLog []string
-}
+// UI draws the battle log. It keeps no game state; the log comes from the engine's view each frame.
+type UI struct{}
 
 func NewUI() *UI {
-	return &UI{
-		battleLog: make([]string, 0),
-	}
+	return &UI{}
 }
 
 func (u *UI) Update() {
 	// Update logic for the UI can be added here
 }
 
-func (u *UI) Draw(screen *ebiten.Image) {
+func (u *UI) Draw(screen *ebiten.Image, log []string) {
 	screen.Fill(color.Black)
 
 	// バトルログを上から下へ表示
-	for i, log := range u.battleLog {
-		y := 10 + i*20
-		if y > 460 {
-			break // 画面下部にはみ出さない
-		}
-		ebitenutil.DebugPrintAt(screen, log, 10, y)
+	for i, line := range VisibleLines(log) {
+		ebitenutil.DebugPrintAt(screen, line, 10, logY(i))
 	}
 }
 
-func (u *UI) AddBattleLog(log string) {
-	u.battleLog = append(u.battleLog, log)
+// logY: i 行目のログの y 座標
+func logY(i int) int {
+	return 10 + i*20
 }
 
-func (u *UI) ClearBattleLog() {
-	u.battleLog = make([]string, 0)
+// VisibleLines returns the leading log lines that fit above the bottom of the screen.
+func VisibleLines(log []string) []string {
+	for i := range log {
+		if logY(i) > 460 {
+			return log[:i] // 画面下部にはみ出さない
+		}
+	}
+	return log
 }
 
 // 任意座標にテキストを描画するユーティリティ
@@ -1,46 +1,39 @@
 package ui
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
-func TestAddBattleLog(t *testing.T) {
+func TestVisibleLines(t *testing.T) {
+	lines := func(n int) []string {
+		log := make([]string, n)
+		for i := range log {
+			log[i] = fmt.Sprint(i)
+		}
+		return log
+	}
 	tests := []struct {
 		name    string
-		logs    []string
+		log     []string
 		wantLen int
 	}{
+		{"empty", nil, 0},
 		{"single log", []string{"test log"}, 1},
 		{"multiple logs", []string{"a", "b", "c"}, 3},
+		{"fills the screen", lines(23), 23},
+		{"cut at the bottom", lines(30), 23},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := NewUI()
-			for _, log := range tt.logs {
-				u.AddBattleLog(log)
-			}
-			if len(u.battleLog) != tt.wantLen {
-				t.Errorf("AddBattleLog failed: got %v", u.battleLog)
-			}
-		})
-	}
-}
-
-func TestClearBattleLog(t *testing.T) {
-	tests := []struct {
-		name string
-		logs []string
-	}{
-		{"clear after add", []string{"log1", "log2"}},
-		{"clear empty", []string{}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			u := NewUI()
-			for _, log := range tt.logs {
-				u.AddBattleLog(log)
+			got := VisibleLines(tt.log)
+			if len(got) != tt.wantLen {
+				t.Errorf("VisibleLines: got %d lines, want %d", len(got), tt.wantLen)
 			}
-			u.ClearBattleLog()
-			if len(u.battleLog) != 0 {
-				t.Errorf("ClearBattleLog failed: got %v", u.battleLog)
+			for i, line := range got {
+				if line != tt.log[i] {
+					t.Errorf("line %d = %q, want %q", i, line, tt.log[i])
+				}
 			}
 		})
 	}
@@ -0,0 +1,90 @@
+package usecase
+
+import "fmt"
+
+// ActionKind is the kind of a semantic input action.
+type ActionKind int
+
+const (
+	ActionDigit   ActionKind = iota // 0-9 のキー（Action.Digit に値）
+	ActionConfirm                   // 決定
+	ActionCancel                    // 取り消し・再入力
+	ActionRetry                     // 同じ seed でやり直す
+)
+
+// Action is one player intent, independent of the device that produced it.
+type Action struct {
+	Kind  ActionKind
+	Digit int // ActionDigit のときの 0-9
+}
+
+var (
+	// ConfirmAction confirms the pending input.
+	ConfirmAction = Action{Kind: ActionConfirm}
+	// CancelAction discards the pending input.
+	CancelAction = Action{Kind: ActionCancel}
+	// RetryAction restarts the game on the same seed after it is over.
+	RetryAction = Action{Kind: ActionRetry}
+)
+
+// DigitAction returns the action for digit key n (0-9).
+func DigitAction(n int) Action {
+	if n < 0 || n > 9 {
+		panic(fmt.Sprintf("Invalid digit: %d", n))
+	}
+	return Action{Kind: ActionDigit, Digit: n}
+}
+
+func (a Action) String() string {
+	switch a.Kind {
+	case ActionDigit:
+		return fmt.Sprintf("digit %d", a.Digit)
+	case ActionConfirm:
+		return "confirm"
+	case ActionCancel:
+		return "cancel"
+	case ActionRetry:
+		return "retry"
+	}
+	return fmt.Sprintf("Action(%d)", int(a.Kind))
+}
+
+// InputSource reports the actions that started in the current frame.
+// Implementations report a held key only on the frame it was pressed.
+type InputSource interface {
+	Actions() []Action
+}
+
+// ScriptedInput is an InputSource that replays a fixed list of frames, for driving the game headlessly.
+type ScriptedInput struct {
+	frames [][]Action
+	next   int
+}
+
+// NewScriptedInput returns a source that yields frames in order, one per call to Actions.
+func NewScriptedInput(frames ...[]Action) *ScriptedInput {
+	return &ScriptedInput{frames: frames}
+}
+
+// ScriptActions returns a source that yields one action per frame.
+func ScriptActions(actions ...Action) *ScriptedInput {
+	frames := make([][]Action, len(actions))
+	for i, a := range actions {
+		frames[i] = []Action{a}
+	}
+	return NewScriptedInput(frames...)
+}
+
+// Actions returns the next frame of the script, or nil once it is exhausted.
+func (s *ScriptedInput) Actions() []Action {
+	if s.Done() {
+		return nil
+	}
+	s.next++
+	return s.frames[s.next-1]
+}
+
+// Done reports whether every frame has been consumed.
+func (s *ScriptedInput) Done() bool {
+	return s.next >= len(s.frames)
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAction_String(t *testing.T) {
+	tests := []struct {
+		a    Action
+		want string
+	}{
+		{DigitAction(0), "digit 0"},
+		{DigitAction(9), "digit 9"},
+		{ConfirmAction, "confirm"},
+		{CancelAction, "cancel"},
+		{RetryAction, "retry"},
+		{Action{Kind: ActionKind(42)}, "Action(42)"},
+	}
+	for _, tt := range tests {
+		if got := tt.a.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestDigitAction_Panics(t *testing.T) {
+	for _, n := range []int{-1, 10} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("DigitAction(%d) should panic", n)
+				}
+			}()
+			DigitAction(n)
+		}()
+	}
+}
+
+func TestScriptedInput(t *testing.T) {
+	tests := []struct {
+		name string
+		src  *ScriptedInput
+		want [][]Action // Actions() の戻り値を順に
+	}{
+		{"empty", NewScriptedInput(), [][]Action{nil}},
+		{"frames", NewScriptedInput([]Action{DigitAction(3), ConfirmAction}, nil, []Action{RetryAction}),
+			[][]Action{{DigitAction(3), ConfirmAction}, nil, {RetryAction}, nil}},
+		{"one action per frame", ScriptActions(DigitAction(1), CancelAction),
+			[][]Action{{DigitAction(1)}, {CancelAction}, nil, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, want := range tt.want {
+				if got := tt.src.Actions(); !reflect.DeepEqual(got, want) {
+					t.Errorf("frame %d: Actions() = %v, want %v", i, got, want)
+				}
+			}
+			if !tt.src.Done() {
+				t.Error("Done() = false after the script")
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"axiom_shift/internal/domain"
+)
+
+func TestDefaultGameConfig(t *testing.T) {
+	c := DefaultGameConfig()
+	player, enemy := c.NewCombatants()
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"size", c.Size(), 3},
+		{"battleMax", c.BattleMax, 10},
+		{"player rows", player.MatrixState.Rows, 3},
+		{"enemy rows", enemy.MatrixState.Rows, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("%s = %d, want %d", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameConfig_NewCombatantsIndependent(t *testing.T) {
+	tests := []struct {
+		name   string
+		config GameConfig
+		mutate func(player *domain.Player, enemy *domain.Enemy)
+	}{
+		{"player input", DefaultGameConfig(), func(p *domain.Player, _ *domain.Enemy) { p.UpdateMatrix(0) }},
+		{"enemy write after reset", DefaultGameConfig(), func(_ *domain.Player, e *domain.Enemy) {
+			e.Reset()
+			e.MatrixState.Data[0][0] = 99
+		}},
+		{"player reset and write", smallBankConfig(), func(p *domain.Player, _ *domain.Enemy) {
+			p.Reset()
+			p.MatrixState.Data[1][1] = 99
+		}},
+		{"enemy growth", smallBankConfig(), func(_ *domain.Player, e *domain.Enemy) {
+			e.Grow(1, domain.NewMatrix([][]float64{{1, 0}, {0, 1}}))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantPlayer, wantEnemy := domain.CopyRows(tt.config.PlayerMatrix), domain.CopyRows(tt.config.EnemyMatrix)
+			player, enemy := tt.config.NewCombatants()
+			tt.mutate(player, enemy)
+			if !reflect.DeepEqual(tt.config.PlayerMatrix, wantPlayer) || !reflect.DeepEqual(tt.config.EnemyMatrix, wantEnemy) {
+				t.Error("NewCombatants should not share matrices with the config")
+			}
+		})
+	}
+}
+
+func TestFallbackSeed_IsValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config GameConfig
+		want   bool
+	}{
+		{"default config", DefaultGameConfig(), true},
+		{"empty matrices", GameConfig{BattleMax: 3, PlayerMatrix: [][]float64{}, PlayerGrowth: 0.5, EnemyName: "E", EnemyMatrix: [][]float64{}, EnemyGrowth: 0.5}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := tt.config.NewCombatants()
+			report, ok := CheckSeed(FallbackSeed, tt.config.BattleMax, player, enemy)
+			if ok != tt.want {
+				t.Fatalf("CheckSeed(FallbackSeed) = %v, want %v", ok, tt.want)
+			}
+			if ok && (len(report.PlayerPath) != tt.config.BattleMax || len(report.EnemyPath) != tt.config.BattleMax) {
+				t.Errorf("paths %v / %v, want %d inputs", report.PlayerPath, report.EnemyPath, tt.config.BattleMax)
+			}
+		})
+	}
+}
+
+func TestUserDataPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		setEnv  func(t *testing.T)
+		wantDir bool
+	}{
+		{"config dir known", func(t *testing.T) { t.Setenv("XDG_CONFIG_HOME", t.TempDir()) }, true},
+		{"config dir unknown", func(t *testing.T) {
+			if runtime.GOOS != "linux" {
+				t.Skip("os.UserConfigDir reads XDG_CONFIG_HOME / HOME only on Linux")
+			}
+			t.Setenv("XDG_CONFIG_HOME", "")
+			t.Setenv("HOME", "")
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setEnv(t)
+			got := UserDataPath("bank.json")
+			if tt.wantDir != (got != "") {
+				t.Fatalf("UserDataPath = %q, want dir %v", got, tt.wantDir)
+			}
+			if got != "" && (filepath.Base(got) != "bank.json" || filepath.Base(filepath.Dir(got)) != "axiom_shift") {
+				t.Errorf("UserDataPath = %q, want .../axiom_shift/bank.json", got)
+			}
+		})
+	}
+}
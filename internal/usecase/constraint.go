@@ -0,0 +1,506 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Constraint is a parsed seed search constraint over a winning input path.
+// A seed satisfies it if the player can win with some input path for which the expression holds.
+//
+// Grammar (keywords are case-sensitive, turns are 1-based):
+//
+//	expr    = and { ("or" | "||") and }
+//	and     = unary { ("and" | "&&") unary }
+//	unary   = ("not" | "!") unary | primary
+//	primary = "(" expr ")" | "true" | "false"
+//	        | ("win" | "lose") "[" N "]"           battle N の勝敗
+//	        | ("any" | "all") "(" expr ")"         いずれか / 全てのターンで成り立つ
+//	        | value op value                       op: == != < <= > >=
+//	value   = NUMBER | ("input" | "key" | "result") "[" N "]"
+//	        | "count" "(" expr ")" | "wins" | "losses" | "turns" | "(" value ")"
+//
+// A parenthesis starts a grouped expr when it can be read as one, otherwise a grouped value,
+// so both "(win[1] or win[2])" and "(input[1]) > 0.5" parse.
+//
+// Inside count / any / all, "input", "key", "result", "win", "lose" and "turn" refer to the turn being examined.
+// input is the value in [0, 1], key the nearest digit key (0-9) and result the battle result.
+// Examples: "lose[3]", "count(key == 0) >= 2", "all(result > -0.5) and wins <= 5".
+type Constraint struct {
+	src     string
+	root    boolExpr
+	maxTurn int
+}
+
+// ConstraintError is a parse error with its position in the source.
+type ConstraintError struct {
+	Pos int // 1 始まりの文字位置（入力の末尾なら len+1）
+	Msg string
+}
+
+// Error implements error.
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("constraint:%d: %s", e.Pos, e.Msg)
+}
+
+// ParseConstraint parses src. Errors are *ConstraintError.
+func ParseConstraint(src string) (*Constraint, error) {
+	tokens, err := lexConstraint(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &constraintParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return &Constraint{src: strings.TrimSpace(src), root: root, maxTurn: p.maxTurn}, nil
+}
+
+// String returns the source of the constraint.
+func (c *Constraint) String() string {
+	return c.src
+}
+
+// MaxTurn returns the largest turn index referenced (0 if none); it must not exceed battleMax.
+func (c *Constraint) MaxTurn() int {
+	return c.maxTurn
+}
+
+// Eval reports whether the constraint holds for a played path.
+// Turns the constraint refers to beyond len(trace.Inputs) make it false.
+func (c *Constraint) Eval(trace PathTrace) bool {
+	if c.maxTurn > len(trace.Inputs) {
+		return false
+	}
+	return c.root.eval(&constraintEnv{trace: trace})
+}
+
+// MarshalJSON encodes the constraint as its source (seed bank のキーに使う).
+func (c *Constraint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.src)
+}
+
+// UnmarshalJSON parses a constraint encoded by MarshalJSON.
+func (c *Constraint) UnmarshalJSON(data []byte) error {
+	var src string
+	if err := json.Unmarshal(data, &src); err != nil {
+		return err
+	}
+	parsed, err := ParseConstraint(src)
+	if err != nil {
+		return err
+	}
+	*c = *parsed
+	return nil
+}
+
+// PathTrace is one played input path with the outcome of every battle.
+type PathTrace struct {
+	Inputs  []float64 // 各ターンの入力 [0, 1]
+	Results []float64 // 各ターンのバトル結果（正ならプレイヤーの勝ち）
+	Wins    []bool
+}
+
+// constraintEnv: 評価中のパスと、count / any / all の中で調べているターン（0 始まり）
+type constraintEnv struct {
+	trace PathTrace
+	turn  int
+}
+
+type boolExpr interface {
+	eval(env *constraintEnv) bool
+}
+
+type numExpr interface {
+	eval(env *constraintEnv) float64
+}
+
+type (
+	boolConst bool
+	notExpr   struct{ x boolExpr }
+	andExpr   struct{ x, y boolExpr }
+	orExpr    struct{ x, y boolExpr }
+	// winAt: turn が -1 なら調べているターン
+	winAt struct {
+		turn int
+		want bool
+	}
+	quantExpr struct {
+		all  bool
+		cond boolExpr
+	}
+	compareExpr struct {
+		op   string
+		x, y numExpr
+	}
+	numConst float64
+	// turnValue: turn が -1 なら調べているターンの値
+	turnValue struct {
+		name string // "input", "key", "result"
+		turn int
+	}
+	countExpr  struct{ cond boolExpr }
+	turnNumber struct{}
+	// outcomeCount: 勝ち数・負け数・ターン数
+	outcomeCount struct{ name string }
+)
+
+func (b boolConst) eval(*constraintEnv) bool { return bool(b) }
+
+func (n notExpr) eval(env *constraintEnv) bool { return !n.x.eval(env) }
+
+func (a andExpr) eval(env *constraintEnv) bool { return a.x.eval(env) && a.y.eval(env) }
+
+func (o orExpr) eval(env *constraintEnv) bool { return o.x.eval(env) || o.y.eval(env) }
+
+func (w winAt) eval(env *constraintEnv) bool {
+	turn := w.turn
+	if turn < 0 {
+		turn = env.turn
+	}
+	return env.trace.Wins[turn] == w.want
+}
+
+func (q quantExpr) eval(env *constraintEnv) bool {
+	inner := *env
+	for inner.turn = 0; inner.turn < len(env.trace.Inputs); inner.turn++ {
+		if q.cond.eval(&inner) != q.all {
+			return !q.all
+		}
+	}
+	return q.all
+}
+
+func (c compareExpr) eval(env *constraintEnv) bool {
+	x, y := c.x.eval(env), c.y.eval(env)
+	switch c.op {
+	case "==":
+		return x == y
+	case "!=":
+		return x != y
+	case "<":
+		return x < y
+	case "<=":
+		return x <= y
+	case ">":
+		return x > y
+	}
+	return x >= y
+}
+
+func (n numConst) eval(*constraintEnv) float64 { return float64(n) }
+
+func (v turnValue) eval(env *constraintEnv) float64 {
+	turn := v.turn
+	if turn < 0 {
+		turn = env.turn
+	}
+	switch v.name {
+	case "input":
+		return env.trace.Inputs[turn]
+	case "key":
+		return math.Round(env.trace.Inputs[turn] * 9)
+	}
+	return env.trace.Results[turn]
+}
+
+func (c countExpr) eval(env *constraintEnv) float64 {
+	inner := *env
+	count := 0
+	for inner.turn = 0; inner.turn < len(env.trace.Inputs); inner.turn++ {
+		if c.cond.eval(&inner) {
+			count++
+		}
+	}
+	return float64(count)
+}
+
+func (turnNumber) eval(env *constraintEnv) float64 { return float64(env.turn + 1) }
+
+func (o outcomeCount) eval(env *constraintEnv) float64 {
+	wins := 0
+	for _, w := range env.trace.Wins {
+		if w {
+			wins++
+		}
+	}
+	switch o.name {
+	case "wins":
+		return float64(wins)
+	case "losses":
+		return float64(len(env.trace.Wins) - wins)
+	}
+	return float64(len(env.trace.Wins))
+}
+
+// --- 字句解析 ---
+
+type constraintTokenKind int
+
+const (
+	tokEOF constraintTokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp // 比較・論理演算子と括弧
+)
+
+type constraintToken struct {
+	kind constraintTokenKind
+	text string
+	pos  int // 1 始まり
+}
+
+// String: エラーメッセージ用の表記
+func (t constraintToken) String() string {
+	if t.kind == tokEOF {
+		return "end of input"
+	}
+	return strconv.Quote(t.text)
+}
+
+// constraintOps: 長いものを先に照合する
+var constraintOps = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]"}
+
+func lexConstraint(src string) ([]constraintToken, error) {
+	var tokens []constraintToken
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9' || c == '.' || c == '-':
+			start := i
+			i++
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			text := src[start:i]
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return nil, &ConstraintError{Pos: start + 1, Msg: fmt.Sprintf("invalid number %q", text)}
+			}
+			tokens = append(tokens, constraintToken{tokNumber, text, start + 1})
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_':
+			start := i
+			for i < len(src) && (src[i] >= 'a' && src[i] <= 'z' || src[i] >= 'A' && src[i] <= 'Z' || src[i] == '_' || src[i] >= '0' && src[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, constraintToken{tokIdent, src[start:i], start + 1})
+		default:
+			op := ""
+			for _, o := range constraintOps {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, &ConstraintError{Pos: i + 1, Msg: fmt.Sprintf("unexpected character %q", src[i:i+1])}
+			}
+			tokens = append(tokens, constraintToken{tokOp, op, i + 1})
+			i += len(op)
+		}
+	}
+	return append(tokens, constraintToken{tokEOF, "", len(src) + 1}), nil
+}
+
+// --- 構文解析（再帰下降） ---
+
+type constraintParser struct {
+	tokens  []constraintToken
+	next    int
+	inTurn  int // count / any / all の入れ子の深さ
+	maxTurn int
+}
+
+func (p *constraintParser) peek() constraintToken {
+	return p.tokens[p.next]
+}
+
+func (p *constraintParser) advance() constraintToken {
+	t := p.tokens[p.next]
+	if t.kind != tokEOF {
+		p.next++
+	}
+	return t
+}
+
+// accept: 次のトークンが text なら読み進めて true
+func (p *constraintParser) accept(text ...string) bool {
+	t := p.peek()
+	for _, s := range text {
+		if t.kind != tokNumber && t.text == s {
+			p.next++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *constraintParser) expect(text string) error {
+	if t := p.peek(); !p.accept(text) {
+		return p.errorf(t, "expected %q, found %s", text, t)
+	}
+	return nil
+}
+
+func (p *constraintParser) errorf(t constraintToken, format string, args ...any) error {
+	return &ConstraintError{Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *constraintParser) parseOr() (boolExpr, error) {
+	x, err := p.parseAnd()
+	for err == nil && p.accept("or", "||") {
+		var y boolExpr
+		if y, err = p.parseAnd(); err == nil {
+			x = orExpr{x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *constraintParser) parseAnd() (boolExpr, error) {
+	x, err := p.parseUnary()
+	for err == nil && p.accept("and", "&&") {
+		var y boolExpr
+		if y, err = p.parseUnary(); err == nil {
+			x = andExpr{x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *constraintParser) parseUnary() (boolExpr, error) {
+	if p.accept("not", "!") {
+		x, err := p.parseUnary()
+		return notExpr{x}, err
+	}
+	return p.parsePrimary()
+}
+
+func (p *constraintParser) parsePrimary() (boolExpr, error) {
+	t := p.peek()
+	switch {
+	case t.text == "(" && t.kind == tokOp:
+		// 括弧は論理式のまとまりとして読み、読めなければ "(input[1]) > 0.5" のような値の比較として読み直す
+		saved := *p
+		p.advance()
+		x, err := p.parseOr()
+		if err == nil {
+			err = p.expect(")")
+		}
+		if err == nil {
+			return x, nil
+		}
+		boolState := *p
+		*p = saved
+		cmp, valueErr := p.parseComparison()
+		if valueErr == nil {
+			return cmp, nil
+		}
+		// 両方読めなければ先まで読めた方の誤りを返す
+		if valueErr.(*ConstraintError).Pos > err.(*ConstraintError).Pos {
+			return nil, valueErr
+		}
+		*p = boolState
+		return nil, err
+	case p.accept("true"):
+		return boolConst(true), nil
+	case p.accept("false"):
+		return boolConst(false), nil
+	case p.accept("win", "lose"):
+		turn, err := p.parseTurn(t)
+		return winAt{turn: turn, want: t.text == "win"}, err
+	case p.accept("any", "all"):
+		cond, err := p.parseTurnCondition()
+		return quantExpr{all: t.text == "all", cond: cond}, err
+	}
+	return p.parseComparison()
+}
+
+// parseComparison: value op value
+func (p *constraintParser) parseComparison() (boolExpr, error) {
+	x, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	op := p.peek()
+	if !p.accept("==", "!=", "<", "<=", ">", ">=") {
+		return nil, p.errorf(op, "expected a comparison operator, found %s", op)
+	}
+	y, err := p.parseValue()
+	return compareExpr{op: op.text, x: x, y: y}, err
+}
+
+func (p *constraintParser) parseValue() (numExpr, error) {
+	t := p.advance()
+	if t.kind == tokOp && t.text == "(" {
+		x, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	}
+	if t.kind == tokNumber {
+		v, _ := strconv.ParseFloat(t.text, 64) // 字句解析で検証済み
+		return numConst(v), nil
+	}
+	if t.kind != tokIdent {
+		return nil, p.errorf(t, "expected a value, found %s", t)
+	}
+	switch t.text {
+	case "input", "key", "result":
+		turn, err := p.parseTurn(t)
+		return turnValue{name: t.text, turn: turn}, err
+	case "count":
+		cond, err := p.parseTurnCondition()
+		return countExpr{cond}, err
+	case "turn":
+		if p.inTurn == 0 {
+			return nil, p.errorf(t, "turn is only defined inside count(), any() or all()")
+		}
+		return turnNumber{}, nil
+	case "wins", "losses", "turns":
+		return outcomeCount{t.text}, nil
+	}
+	return nil, p.errorf(t, "unknown name %q", t.text)
+}
+
+// parseTurn: name の後の "[N]" を読み、0 始まりのターンを返す
+// 省略できるのは count / any / all の中だけで、その場合は -1（調べているターン）
+func (p *constraintParser) parseTurn(name constraintToken) (int, error) {
+	if !p.accept("[") {
+		if p.inTurn == 0 {
+			return 0, p.errorf(name, "%s needs a turn outside count(), any() or all(), e.g. %s[1]", name.text, name.text)
+		}
+		return -1, nil
+	}
+	t := p.advance()
+	n, err := strconv.Atoi(t.text)
+	if t.kind != tokNumber || err != nil || n < 1 {
+		return 0, p.errorf(t, "turn must be an integer >= 1, found %s", t)
+	}
+	p.maxTurn = max(p.maxTurn, n)
+	return n - 1, p.expect("]")
+}
+
+// parseTurnCondition: count / any / all の "(expr)"。中ではターンを省略できる
+func (p *constraintParser) parseTurnCondition() (boolExpr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	p.inTurn++
+	cond, err := p.parseOr()
+	p.inTurn--
+	if err != nil {
+		return nil, err
+	}
+	return cond, p.expect(")")
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"axiom_shift/internal/logic"
+)
+
+// sampleTrace: 4 ターン、キー 0, 0, 9, 5 で 勝ち・負け・負け・勝ち
+func sampleTrace() PathTrace {
+	return PathTrace{
+		Inputs:  []float64{0, 0, 1, 5.0 / 9},
+		Results: []float64{0.4, -0.2, -0.7, 0.1},
+		Wins:    []bool{true, false, false, true},
+	}
+}
+
+func TestConstraint_Eval(t *testing.T) {
+	tests := []struct {
+		src     string
+		want    bool
+		maxTurn int
+	}{
+		{"true", true, 0},
+		{"false", false, 0},
+		{"lose[3]", true, 3},
+		{"win[3]", false, 3},
+		{"lose[2] && lose[3]", true, 3},
+		{"not lose[2]", false, 2},
+		{"!win[1] || win[4]", true, 4},
+		{"count(key == 0) >= 2", true, 0},
+		{"count(key == 0) > 2", false, 0},
+		{"count(input == 1) == 1", true, 0},
+		{"key[4] == 5", true, 4},
+		{"input[3] >= 0.99", true, 3},
+		{"result[3] < -0.5", true, 3},
+		{"result[1] <= 0.4 and result[1] != 0", true, 1},
+		{"all(result > -0.5)", false, 0},
+		{"all(result > -1)", true, 0},
+		{"any(lose and key == 9)", true, 0},
+		{"any(win and turn == 2)", false, 0},
+		{"count(win) == wins and wins == 2 and losses == 2 and turns == 4", true, 0},
+		{"(lose[2] or win[2]) and not (win[1] and lose[1])", true, 2},
+		{"count(any(lose)) == turns", true, 0}, // 入れ子の any は全ターンを調べる
+		{"(input[3]) >= 0.99", true, 3},        // 括弧で囲んだ値の比較
+		{"((key[4])) == 5 and (win[1])", true, 4},
+		{"(count(key == 0)) > (wins)", false, 0},
+		{"win[5]", false, 5}, // パスより先のターンは満たさない
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			c, err := ParseConstraint(tt.src)
+			if err != nil {
+				t.Fatalf("ParseConstraint error: %v", err)
+			}
+			if got := c.Eval(sampleTrace()); got != tt.want {
+				t.Errorf("Eval = %v, want %v", got, tt.want)
+			}
+			if c.MaxTurn() != tt.maxTurn {
+				t.Errorf("MaxTurn = %d, want %d", c.MaxTurn(), tt.maxTurn)
+			}
+			if c.String() != tt.src {
+				t.Errorf("String = %q, want %q", c.String(), tt.src)
+			}
+		})
+	}
+}
+
+func TestParseConstraint_Errors(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantPos int
+		wantMsg string
+	}{
+		{"", 1, `expected a value, found end of input`},
+		{"lose[3] and", 12, `expected a value, found end of input`},
+		{"lose[0]", 6, `turn must be an integer >= 1, found "0"`},
+		{"lose[1.5]", 6, `turn must be an integer >= 1, found "1.5"`},
+		{"lose[3", 7, `expected "]", found end of input`},
+		{"lose", 1, `lose needs a turn outside count(), any() or all(), e.g. lose[1]`},
+		{"input == 0", 1, `input needs a turn outside count(), any() or all(), e.g. input[1]`},
+		{"turn == 1", 1, `turn is only defined inside count(), any() or all()`},
+		{"count(key == 0)", 16, `expected a comparison operator, found end of input`},
+		{"count key == 0", 7, `expected "(", found "key"`},
+		{"(win[1]", 8, `expected ")", found end of input`},
+		{"(input[1]) >", 13, `expected a value, found end of input`},
+		{"(input[1] > 0.5", 16, `expected ")", found end of input`},
+		{"(input[1]", 10, `expected a comparison operator, found end of input`},
+		{"wins = 2", 6, `unexpected character "="`},
+		{"wins == 1..2", 9, `invalid number "1..2"`},
+		{"wins == -", 9, `invalid number "-"`},
+		{"inputs[1] > 0", 1, `unknown name "inputs"`},
+		{"win[1] win[2]", 8, `unexpected "win"`},
+		{"wins == )", 9, `expected a value, found ")"`},
+		{"wins == 2 @", 11, `unexpected character "@"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := ParseConstraint(tt.src)
+			var ce *ConstraintError
+			if !errors.As(err, &ce) {
+				t.Fatalf("error = %v, want *ConstraintError", err)
+			}
+			if ce.Pos != tt.wantPos || ce.Msg != tt.wantMsg {
+				t.Errorf("error at %d %q, want at %d %q", ce.Pos, ce.Msg, tt.wantPos, tt.wantMsg)
+			}
+			if ce.Error() == "" {
+				t.Error("Error() should not be empty")
+			}
+		})
+	}
+}
+
+func TestConstraint_JSON(t *testing.T) {
+	c, err := ParseConstraint("  lose[3] and count(key == 0) >= 2 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(SeedCriteria{MaxWinRate: 1, Constraint: c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got SeedCriteria
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal %s: %v", data, err)
+	}
+	if got.Constraint == nil || got.Constraint.String() != "lose[3] and count(key == 0) >= 2" || got.Constraint.MaxTurn() != 3 {
+		t.Errorf("round trip = %+v", got.Constraint)
+	}
+	// 制約なしの基準は制約の導入前と同じ JSON になる
+	if data, _ := json.Marshal(DefaultSeedCriteria()); string(data) != `{"MinWinRate":0,"MaxWinRate":1,"MinWinningFirstMoves":0,"MinDecisiveDepth":0,"Quality":{"AllowTrivial":false,"AllowOneMoveDecided":false,"AllowExactSequence":false,"MinFirstMoveEntropy":0,"MinLateSensitivity":0}}` {
+		t.Errorf("default criteria JSON = %s", data)
+	}
+	for _, bad := range []string{`{"Constraint":"lose["}`, `{"Constraint":3}`} {
+		if err := json.Unmarshal([]byte(bad), &got); err == nil {
+			t.Errorf("Unmarshal(%s) should fail", bad)
+		}
+	}
+}
+
+func TestFindSeed_Constraint(t *testing.T) {
+	config := smallBankConfig()
+	config.BattleMax = 5
+	tests := []struct {
+		name           string
+		src            string
+		granularity    InputGranularity
+		wantFound      bool
+		wantConstraint bool // 制約による棄却が必ず起こるか
+	}{
+		{"lose a middle battle", "lose[3]", InputDigits, true, false},
+		{"input used twice", "count(key == 0) >= 2", InputDigits, true, false},
+		{"continuous inputs", "lose[2] and input[1] > 0.5", InputContinuous, true, false},
+		{"impossible", "false", InputDigits, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseConstraint(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			criteria := DefaultSeedCriteria()
+			criteria.Constraint = c
+			player, enemy := config.NewCombatants()
+			var last SeedProgress
+			opts := SeedSearchOptions{Granularity: tt.granularity, MaxTries: 30, Progress: func(p SeedProgress) { last = p }}
+			r, err := FindSeed(context.Background(), config.BattleMax, player, enemy, logic.NewSeedManagerWithFixedValue(5), criteria, opts)
+			if (err == nil) != tt.wantFound {
+				t.Fatalf("FindSeed error = %v, want found %v", err, tt.wantFound)
+			}
+			if tt.wantConstraint && last.ConstraintRejected == 0 {
+				t.Errorf("ConstraintRejected = %d", last.ConstraintRejected)
+			}
+			if err != nil {
+				return
+			}
+			// 代表の勝ちパスが制約を満たす
+			e := newSeedEvaluator(config.BattleMax, player, enemy, criteria, opts)
+			trace := e.tracePath(NewRuleForSeed(r.Seed, config.Size()), r.PlayerInputs)
+			if !trace.Wins[config.BattleMax-1] || !c.Eval(trace) {
+				t.Errorf("witness %v does not satisfy %q: %+v", r.PlayerInputs, tt.src, trace)
+			}
+			if tt.granularity == InputDigits && (len(r.PlayerPath) != config.BattleMax || !e.playPath(NewRuleForSeed(r.Seed, config.Size()), r.PlayerPath)) {
+				t.Errorf("PlayerPath %v does not match the witness", r.PlayerPath)
+			}
+		})
+	}
+}
+
+func TestFindSeed_ConstraintBeyondBattleMax(t *testing.T) {
+	tests := []struct {
+		name      string
+		expr      string
+		battleMax int
+		wantPanic bool
+	}{
+		{"win after the last battle", "win[4]", 3, true},
+		{"input after the last battle", "input[5] > 0.5", 3, true},
+		{"nested in a boolean expression", "win[1] or lose[4]", 3, true},
+		{"last battle", "win[3]", 3, false},
+		{"no turn", "count(key == 0) >= 0", 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseConstraint(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q): %v", tt.expr, err)
+			}
+			criteria := DefaultSeedCriteria()
+			criteria.Constraint = c
+			player, enemy := smallBankConfig().NewCombatants()
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("panic = %v, want panic %v", r, tt.wantPanic)
+				}
+			}()
+			FindSeed(context.Background(), tt.battleMax, player, enemy, logic.NewSeedManagerWithFixedValue(1), criteria, SeedSearchOptions{})
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/logic"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Clock returns the current time. It is injected so the daily mode is testable.
+type Clock func() time.Time
+
+// dailyKeyLayout: 日付キーは UTC の YYYY-MM-DD
+const dailyKeyLayout = "2006-01-02"
+
+// DailyKey returns the UTC calendar date of t used to identify a daily challenge.
+func DailyKey(t time.Time) string {
+	return t.UTC().Format(dailyKeyLayout)
+}
+
+// DailySeed returns the root seed for the UTC date of t.
+// Everyone gets the same value on the same day regardless of time zone.
+func DailySeed(t time.Time) int64 {
+	u := t.UTC()
+	root := int64(u.Year()*10000 + int(u.Month())*100 + u.Day())
+	return logic.DeriveSeed(root, "daily")
+}
+
+// FindDailySeed runs the normal validity search from the daily root seed,
+// so every player ends up with the same puzzle for the day.
+func FindDailySeed(ctx context.Context, t time.Time, battleMax int, player *domain.Player, enemy *domain.Enemy, opts SeedSearchOptions) (SeedReport, error) {
+	return FindValidSeed(ctx, battleMax, player, enemy, logic.NewSeedManagerWithFixedValue(DailySeed(t)), opts)
+}
+
+// DailyResult is the local record of one day's challenge.
+type DailyResult struct {
+	Attempts   int     `json:"attempts"`
+	Won        bool    `json:"won"`
+	BestResult float64 `json:"best_result"` // 最終戦の結果値の最大
+}
+
+// DailyBook stores daily results keyed by DailyKey.
+type DailyBook struct {
+	Results map[string]DailyResult `json:"results"`
+}
+
+// NewDailyBook returns an empty book.
+func NewDailyBook() *DailyBook {
+	return &DailyBook{Results: make(map[string]DailyResult)}
+}
+
+// Record adds one finished attempt for the date of t.
+func (b *DailyBook) Record(t time.Time, win bool, finalResult float64) {
+	key := DailyKey(t)
+	r, ok := b.Results[key]
+	if !ok || finalResult > r.BestResult {
+		r.BestResult = finalResult
+	}
+	r.Attempts++
+	r.Won = r.Won || win
+	b.Results[key] = r
+}
+
+// Result returns the record for the date of t.
+func (b *DailyBook) Result(t time.Time) (DailyResult, bool) {
+	r, ok := b.Results[DailyKey(t)]
+	return r, ok
+}
+
+// Streak counts consecutive won days ending today. A day not yet won today
+// does not break the streak until it is over.
+func (b *DailyBook) Streak(today time.Time) int {
+	day := today.UTC()
+	if r, ok := b.Result(day); !ok || !r.Won {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for {
+		r, ok := b.Result(day)
+		if !ok || !r.Won {
+			return streak
+		}
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+}
+
+// LoadDailyBook reads a book from path. A missing file yields an empty book.
+func LoadDailyBook(path string) (*DailyBook, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewDailyBook(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b := NewDailyBook()
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, err
+	}
+	if b.Results == nil {
+		b.Results = make(map[string]DailyResult)
+	}
+	return b, nil
+}
+
+// Save writes the book to path, creating parent directories as needed.
+func (b *DailyBook) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// DailyChallenge ties the daily mode to a clock and a persisted book.
+type DailyChallenge struct {
+	Now  Clock
+	Book *DailyBook
+	Path string // 空なら保存しない
+}
+
+// NewDailyChallenge loads the book at path. now defaults to time.Now.
+func NewDailyChallenge(now Clock, path string) (*DailyChallenge, error) {
+	if now == nil {
+		now = time.Now
+	}
+	book := NewDailyBook()
+	if path != "" {
+		var err error
+		if book, err = LoadDailyBook(path); err != nil {
+			return nil, err
+		}
+	}
+	return &DailyChallenge{Now: now, Book: book, Path: path}, nil
+}
+
+// Today returns the UTC date key of the current challenge.
+func (d *DailyChallenge) Today() string {
+	return DailyKey(d.Now())
+}
+
+// FindSeed finds the seed of the puzzle for day. Take day from Now when the daily game starts
+// and pass the same value to Record, so a game finished after midnight counts for its own puzzle.
+func (d *DailyChallenge) FindSeed(ctx context.Context, day time.Time, battleMax int, player *domain.Player, enemy *domain.Enemy, opts SeedSearchOptions) (SeedReport, error) {
+	return FindDailySeed(ctx, day, battleMax, player, enemy, opts)
+}
+
+// Record stores a finished attempt at the puzzle for day and saves the book.
+func (d *DailyChallenge) Record(day time.Time, win bool, finalResult float64) error {
+	d.Book.Record(day, win, finalResult)
+	if d.Path == "" {
+		return nil
+	}
+	return d.Book.Save(d.Path)
+}
+
+// Streak returns the current streak as of today.
+func (d *DailyChallenge) Streak() int {
+	return d.Book.Streak(d.Now())
+}
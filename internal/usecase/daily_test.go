@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d, h int, loc *time.Location) time.Time {
+	return time.Date(y, m, d, h, 0, 0, 0, loc)
+}
+
+func TestDailySeed(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		name  string
+		a, b  time.Time
+		equal bool
+	}{
+		{"same day different hour", date(2026, 10, 19, 1, time.UTC), date(2026, 10, 19, 23, time.UTC), true},
+		{"same UTC date in another zone", date(2026, 10, 19, 12, time.UTC), date(2026, 10, 19, 21, tokyo), true},
+		{"local date differs from UTC date", date(2026, 10, 20, 8, tokyo), date(2026, 10, 20, 12, time.UTC), false},
+		{"next day", date(2026, 10, 19, 12, time.UTC), date(2026, 10, 20, 12, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DailySeed(tt.a) == DailySeed(tt.b); got != tt.equal {
+				t.Errorf("DailySeed equal = %v, want %v (%s / %s)", got, tt.equal, DailyKey(tt.a), DailyKey(tt.b))
+			}
+		})
+	}
+}
+
+func TestFindDailySeed_Deterministic(t *testing.T) {
+	tests := []struct {
+		name      string
+		day       time.Time
+		battleMax int
+	}{
+		{"three battles", date(2026, 10, 19, 0, time.UTC), 3},
+		{"four battles", date(2026, 10, 19, 0, time.UTC), 4},
+		{"next day", date(2026, 10, 20, 0, time.UTC), 3},
+		{"other time zone", date(2026, 10, 19, 23, time.FixedZone("JST", 9*60*60)), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := func() int64 {
+				player, enemy := newProofPair()
+				report, err := FindDailySeed(context.Background(), tt.day, tt.battleMax, player, enemy, SeedSearchOptions{})
+				if err != nil {
+					t.Fatalf("FindDailySeed error: %v", err)
+				}
+				return report.Seed
+			}
+			if a, b := run(), run(); a != b {
+				t.Errorf("FindDailySeed not deterministic: %d vs %d", a, b)
+			}
+		})
+	}
+}
+
+func TestDailyBook_Record(t *testing.T) {
+	day := date(2026, 10, 19, 10, time.UTC)
+	tests := []struct {
+		name    string
+		results []struct {
+			win   bool
+			value float64
+		}
+		want DailyResult
+	}{
+		{"single loss", []struct {
+			win   bool
+			value float64
+		}{{false, -0.3}}, DailyResult{Attempts: 1, Won: false, BestResult: -0.3}},
+		{"loss then win", []struct {
+			win   bool
+			value float64
+		}{{false, -0.3}, {true, 0.2}}, DailyResult{Attempts: 2, Won: true, BestResult: 0.2}},
+		{"win then worse loss keeps best", []struct {
+			win   bool
+			value float64
+		}{{true, 0.4}, {false, -0.1}}, DailyResult{Attempts: 2, Won: true, BestResult: 0.4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewDailyBook()
+			for _, r := range tt.results {
+				b.Record(day, r.win, r.value)
+			}
+			got, ok := b.Result(day)
+			if !ok || got != tt.want {
+				t.Errorf("Result = %+v (%v), want %+v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailyBook_Streak(t *testing.T) {
+	today := date(2026, 10, 19, 12, time.UTC)
+	tests := []struct {
+		name string
+		won  map[int]bool // 今日からの日数オフセット → 勝敗
+		want int
+	}{
+		{"no records", map[int]bool{}, 0},
+		{"won today only", map[int]bool{0: true}, 1},
+		{"three days in a row", map[int]bool{0: true, -1: true, -2: true}, 3},
+		{"today not played yet keeps streak", map[int]bool{-1: true, -2: true}, 2},
+		{"today lost so far keeps streak", map[int]bool{0: false, -1: true}, 1},
+		{"gap breaks streak", map[int]bool{0: true, -2: true}, 1},
+		{"lost yesterday", map[int]bool{-1: false, -2: true}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewDailyBook()
+			for off, win := range tt.won {
+				b.Record(today.AddDate(0, 0, off), win, 0)
+			}
+			if got := b.Streak(today); got != tt.want {
+				t.Errorf("Streak = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailyBook_LoadSave(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	nullResults := filepath.Join(dir, "null.json")
+	if err := os.WriteFile(nullResults, []byte(`{"results":null}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+		wantLen int
+	}{
+		{"missing file", filepath.Join(dir, "missing.json"), false, 0},
+		{"corrupt file", corrupt, true, 0},
+		{"null results", nullResults, false, 0},
+		{"directory", dir, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := LoadDailyBook(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadDailyBook error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(b.Results) != tt.wantLen {
+				t.Errorf("Results len = %d, want %d", len(b.Results), tt.wantLen)
+			}
+		})
+	}
+
+	path := filepath.Join(dir, "nested", "daily.json")
+	b := NewDailyBook()
+	b.Record(date(2026, 10, 19, 0, time.UTC), true, 0.5)
+	if err := b.Save(path); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	loaded, err := LoadDailyBook(path)
+	if err != nil {
+		t.Fatalf("LoadDailyBook error: %v", err)
+	}
+	if got := loaded.Results["2026-10-19"]; got != (DailyResult{Attempts: 1, Won: true, BestResult: 0.5}) {
+		t.Errorf("round trip = %+v", got)
+	}
+	if err := b.Save(filepath.Join(corrupt, "x.json")); err == nil {
+		t.Error("Save under a file should fail")
+	}
+}
+
+func TestDailyChallenge(t *testing.T) {
+	start := date(2026, 10, 19, 12, time.UTC)
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		fixed      bool      // false なら既定の時計（time.Now）
+		finish     time.Time // ゲームを終えて記録する時刻
+		path       string
+		wantErr    bool
+		wantToday  string // 記録時の Today
+		wantStreak int
+	}{
+		{"in memory", true, start, "", false, "2026-10-19", 1},
+		{"persisted", true, start, filepath.Join(dir, "daily.json"), false, "2026-10-19", 1},
+		// 日付をまたいで終えたゲームは始めた日のパズルとして記録され、翌日の連続記録も途切れない
+		{"finished after midnight", true, date(2026, 10, 20, 0, time.UTC).Add(5 * time.Minute), "", false, "2026-10-20", 1},
+		{"default clock", false, time.Time{}, "", false, "", 1},
+		{"corrupt book", true, start, corrupt, true, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := start
+			var clock Clock
+			if tt.fixed {
+				clock = func() time.Time { return now }
+			}
+			d, err := NewDailyChallenge(clock, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewDailyChallenge error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			day := d.Now() // デイリーを始めた時刻
+			if tt.fixed {
+				now = tt.finish
+			}
+			if err := d.Record(day, true, 0.1); err != nil {
+				t.Fatalf("Record error: %v", err)
+			}
+			if tt.wantToday != "" && d.Today() != tt.wantToday {
+				t.Errorf("Today = %s, want %s", d.Today(), tt.wantToday)
+			}
+			if r, ok := d.Book.Result(day); !ok || r.Attempts != 1 || !r.Won {
+				t.Errorf("result for %s = %+v, %v", DailyKey(day), r, ok)
+			}
+			if DailyKey(d.Now()) != DailyKey(day) {
+				if _, ok := d.Book.Result(d.Now()); ok {
+					t.Errorf("attempt also recorded for %s", d.Today())
+				}
+			}
+			if d.Streak() != tt.wantStreak {
+				t.Errorf("Streak = %d, want %d", d.Streak(), tt.wantStreak)
+			}
+			if tt.path != "" {
+				reloaded, err := NewDailyChallenge(clock, tt.path)
+				if err != nil || reloaded.Streak() != tt.wantStreak {
+					t.Errorf("reloaded streak = %d (%v), want %d", reloaded.Streak(), err, tt.wantStreak)
+				}
+			}
+		})
+	}
+}
+
+func TestDailyChallenge_FindSeed(t *testing.T) {
+	now := date(2026, 10, 19, 12, time.UTC)
+	tests := []struct {
+		name      string
+		day       time.Time // 探す日（開始時に Now から取った日付）
+		battleMax int
+	}{
+		{"today", now, 3},
+		// 日付をまたいでも開始した日のパズルを探す
+		{"started yesterday", now.AddDate(0, 0, -1), 3},
+		{"four battles", now, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := NewDailyChallenge(func() time.Time { return now }, "")
+			player, enemy := newProofPair()
+			got, err := d.FindSeed(context.Background(), tt.day, tt.battleMax, player, enemy, SeedSearchOptions{})
+			if err != nil {
+				t.Fatalf("FindSeed error: %v", err)
+			}
+			want, _ := FindDailySeed(context.Background(), tt.day, tt.battleMax, player, enemy, SeedSearchOptions{})
+			if got.Seed != want.Seed {
+				t.Errorf("FindSeed = %d, want %d", got.Seed, want.Seed)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"axiom_shift/internal/logic"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// InputGranularity is how finely the player may choose the input in [0, 1] each turn.
+// The zero value is the classic ten digit keys.
+type InputGranularity int
+
+const (
+	InputDigits     InputGranularity = iota // 0〜9 キー、1/9 刻み 10 段階（既定）
+	InputPercent                            // 1/99 刻み 100 段階
+	InputContinuous                         // [0, 1] の任意の実数
+)
+
+// String returns the display name of the granularity.
+func (g InputGranularity) String() string {
+	switch g {
+	case InputPercent:
+		return "100"
+	case InputContinuous:
+		return "continuous"
+	}
+	return "10"
+}
+
+// ParseInputGranularity parses a granularity name as returned by String (case-insensitive).
+func ParseInputGranularity(s string) (InputGranularity, error) {
+	for g := InputDigits; g <= InputContinuous; g++ {
+		if strings.EqualFold(s, g.String()) {
+			return g, nil
+		}
+	}
+	return InputDigits, fmt.Errorf("unknown input granularity %q (want 10, 100 or continuous)", s)
+}
+
+// Levels returns the number of distinct inputs, or 0 for continuous input.
+func (g InputGranularity) Levels() int {
+	switch g {
+	case InputPercent:
+		return 100
+	case InputContinuous:
+		return 0
+	}
+	return 10
+}
+
+// steps: 隣り合う入力値の間隔の逆数（連続なら 0）
+func (g InputGranularity) steps() int {
+	if l := g.Levels(); l > 0 {
+		return l - 1
+	}
+	return 0
+}
+
+// Value returns the input of level k (0 <= k < Levels), e.g. digit key k for InputDigits.
+func (g InputGranularity) Value(k int) float64 {
+	if g.steps() == 0 {
+		panic("Invalid granularity: continuous input has no levels")
+	}
+	return float64(k) / float64(g.steps())
+}
+
+// Quantize clamps x to [0, 1] and snaps it to the nearest input the granularity allows.
+func (g InputGranularity) Quantize(x float64) float64 {
+	x = math.Min(math.Max(x, 0), 1)
+	if g.steps() == 0 {
+		return x
+	}
+	return g.Value(int(math.Round(x * float64(g.steps()))))
+}
+
+// ParseInput parses a typed input: a level (0-9 or 0-99) or, for any granularity,
+// a decimal with a point in [0, 1] such as "0.37". Decimals are quantized.
+func (g InputGranularity) ParseInput(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, ".") && g.steps() > 0 {
+		k, err := strconv.Atoi(s)
+		if err != nil || k < 0 || k > g.steps() {
+			return 0, fmt.Errorf("input %q must be a level from 0 to %d", s, g.steps())
+		}
+		return g.Value(k), nil
+	}
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(x) || x < 0 || x > 1 {
+		return 0, fmt.Errorf("input %q must be a number from 0 to 1", s)
+	}
+	return g.Quantize(x), nil
+}
+
+// FormatInput formats an input the way the player would type it for the granularity.
+func (g InputGranularity) FormatInput(x float64) string {
+	if g.steps() == 0 {
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	return strconv.Itoa(int(math.Round(x * float64(g.steps()))))
+}
+
+// random: 一様に選んだ入力（InputDigits では従来どおり Intn(10) を 1 回だけ消費する）
+func (g InputGranularity) random(rng logic.RandomSource) float64 {
+	if g.steps() == 0 {
+		return rng.Float64()
+	}
+	return g.Value(rng.Intn(g.Levels()))
+}
+
+// mirror: 対称変量。[0, 1] を反転した入力（InputDigits では 9-x キー）
+func (g InputGranularity) mirror(x float64) float64 {
+	if g.steps() == 0 {
+		return 1 - x
+	}
+	return g.Value(g.steps() - int(math.Round(x*float64(g.steps()))))
+}
+
+// firstMoveClasses: 初手の分類数。初手の多様性はどの粒度でも 10 区分で数える
+const firstMoveClasses = 10
+
+// firstMoveClass: 入力の初手区分（InputDigits ではキーそのもの、それ以外は 0.1 刻みの区間）
+func (g InputGranularity) firstMoveClass(x float64) int {
+	if g == InputDigits {
+		return int(math.Round(x * 9))
+	}
+	return min(int(x*firstMoveClasses), firstMoveClasses-1)
+}
+
+// classInput: 初手区分 c に属する入力を 1 つ選ぶ（InputDigits では乱数を消費せずキー c）
+func (g InputGranularity) classInput(rng logic.RandomSource, c int) float64 {
+	switch {
+	case g == InputDigits:
+		return g.Value(c)
+	case g.steps() == 0:
+		return (float64(c) + rng.Float64()) / firstMoveClasses
+	}
+	// 区分 c に入るレベルの範囲 [lo, hi]（最後の区分は 1.0 を含む）
+	steps := float64(g.steps())
+	lo := int(math.Ceil(float64(c) * steps / firstMoveClasses))
+	hi := g.steps()
+	if c < firstMoveClasses-1 {
+		hi = int(math.Ceil(float64(c+1)*steps/firstMoveClasses)) - 1
+	}
+	return g.Value(lo + rng.Intn(hi-lo+1))
+}
+
+// minStep: 座標探索で試す最小の刻み幅（連続入力では 2^-20）
+func (g InputGranularity) minStep() float64 {
+	if g.steps() == 0 {
+		return 1.0 / (1 << 20)
+	}
+	return 1 / float64(g.steps())
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"math"
+	"testing"
+
+	"axiom_shift/internal/logic"
+)
+
+func TestInputGranularity_Names(t *testing.T) {
+	tests := []struct {
+		g      InputGranularity
+		name   string
+		levels int
+	}{
+		{InputDigits, "10", 10},
+		{InputPercent, "100", 100},
+		{InputContinuous, "continuous", 0},
+	}
+	for _, tt := range tests {
+		if got := tt.g.String(); got != tt.name {
+			t.Errorf("String() = %q, want %q", got, tt.name)
+		}
+		if got := tt.g.Levels(); got != tt.levels {
+			t.Errorf("%s: Levels() = %d, want %d", tt.g, got, tt.levels)
+		}
+		if got, err := ParseInputGranularity(tt.name); err != nil || got != tt.g {
+			t.Errorf("ParseInputGranularity(%q) = %v, %v", tt.name, got, err)
+		}
+	}
+	if got, err := ParseInputGranularity("Continuous"); err != nil || got != InputContinuous {
+		t.Errorf("ParseInputGranularity should be case-insensitive: %v, %v", got, err)
+	}
+	if _, err := ParseInputGranularity("1000"); err == nil {
+		t.Error("ParseInputGranularity(1000) should fail")
+	}
+}
+
+func TestInputGranularity_Quantize(t *testing.T) {
+	tests := []struct {
+		g    InputGranularity
+		x    float64
+		want float64
+	}{
+		{InputDigits, 0.37, 3.0 / 9},
+		{InputDigits, 1.5, 1},
+		{InputDigits, -0.2, 0},
+		{InputPercent, 0.37, 37.0 / 99},
+		{InputPercent, 0.005, 0},
+		{InputContinuous, 0.37, 0.37},
+		{InputContinuous, 2, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.g.Quantize(tt.x); got != tt.want {
+			t.Errorf("%s: Quantize(%v) = %v, want %v", tt.g, tt.x, got, tt.want)
+		}
+	}
+	// 10 キーの値はどの粒度でもそのまま入力できる
+	for k := 0; k < 10; k++ {
+		for _, g := range []InputGranularity{InputPercent, InputContinuous} {
+			if x := InputDigits.Value(k); math.Abs(g.Quantize(x)-x) > 1e-15 {
+				t.Errorf("%s cannot represent digit %d: %v", g, k, g.Quantize(x))
+			}
+		}
+	}
+}
+
+func TestInputGranularity_Value(t *testing.T) {
+	tests := []struct {
+		name      string
+		g         InputGranularity
+		k         int
+		want      float64
+		wantPanic bool
+	}{
+		{"digit 0", InputDigits, 0, 0, false},
+		{"digit 7", InputDigits, 7, 7.0 / 9, false},
+		{"digit 9", InputDigits, 9, 1, false},
+		{"percent 42", InputPercent, 42, 42.0 / 99, false},
+		{"percent 99", InputPercent, 99, 1, false},
+		{"continuous has no levels", InputContinuous, 1, 0, true},
+		{"continuous level 0", InputContinuous, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("panic = %v, want panic %v", r, tt.wantPanic)
+				}
+			}()
+			if got := tt.g.Value(tt.k); got != tt.want {
+				t.Errorf("Value(%d) = %v, want %v", tt.k, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputGranularity_ParseFormat(t *testing.T) {
+	tests := []struct {
+		g       InputGranularity
+		in      string
+		want    float64
+		wantErr bool
+		format  string
+	}{
+		{InputDigits, "7", 7.0 / 9, false, "7"},
+		{InputDigits, " 0.5 ", 5.0 / 9, false, "5"},
+		{InputDigits, "10", 0, true, ""},
+		{InputPercent, "42", 42.0 / 99, false, "42"},
+		{InputPercent, "99", 1, false, "99"},
+		{InputPercent, "100", 0, true, ""},
+		{InputContinuous, "0.375", 0.375, false, "0.375"},
+		{InputContinuous, "1", 1, false, "1"},
+		{InputContinuous, "1.2", 0, true, ""},
+		{InputContinuous, "-0.1", 0, true, ""},
+		{InputContinuous, "NaN", 0, true, ""},
+		{InputContinuous, "abc", 0, true, ""},
+	}
+	for _, tt := range tests {
+		got, err := tt.g.ParseInput(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: ParseInput(%q) = %v, %v; want %v (error %v)", tt.g, tt.in, got, err, tt.want, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr {
+			if f := tt.g.FormatInput(got); f != tt.format {
+				t.Errorf("%s: FormatInput(%v) = %q, want %q", tt.g, got, f, tt.format)
+			}
+		}
+	}
+}
+
+func TestInputGranularity_Sampling(t *testing.T) {
+	rng := logic.NewSeedManagerWithFixedValue(1)
+	for _, g := range []InputGranularity{InputDigits, InputPercent, InputContinuous} {
+		t.Run(g.String(), func(t *testing.T) {
+			for i := 0; i < 200; i++ {
+				x := g.random(rng)
+				if x < 0 || x > 1 || g.Quantize(x) != x {
+					t.Fatalf("random() = %v is not a valid input", x)
+				}
+				if m := g.mirror(x); math.Abs(m-(1-x)) > 1e-12 || g.Quantize(m) != m {
+					t.Fatalf("mirror(%v) = %v, want 1-x on the grid", x, m)
+				}
+			}
+			for c := 0; c < firstMoveClasses; c++ {
+				for i := 0; i < 50; i++ {
+					x := g.classInput(rng, c)
+					if got := g.firstMoveClass(x); got != c || g.Quantize(x) != x {
+						t.Fatalf("classInput(%d) = %v in class %d", c, x, got)
+					}
+				}
+			}
+			if g.minStep() <= 0 || g.minStep() > 1.0/9 {
+				t.Errorf("minStep() = %v", g.minStep())
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package usecase
+
+// GameLoop drives a GameSession through the in-game phases (input, confirm, battle, end)
+// from the actions of an InputSource, one frame per Step. Other phases (menu, loading, reveal)
+// belong to the front end and are left untouched.
+type GameLoop struct {
+	session *GameSession
+	phase   *PhaseMachine
+	input   InputSource
+	onTurn  []func(TurnRecord)
+}
+
+// NewGameLoop returns a loop over session that moves phase along its transition table.
+func NewGameLoop(session *GameSession, phase *PhaseMachine, input InputSource) *GameLoop {
+	if session == nil || phase == nil || input == nil {
+		panic("Invalid parameters: session, phase and input must not be nil")
+	}
+	return &GameLoop{session: session, phase: phase, input: input}
+}
+
+// OnTurn registers fn to run after every battle, before the phase moves on.
+func (l *GameLoop) OnTurn(fn func(TurnRecord)) {
+	l.onTurn = append(l.onTurn, fn)
+}
+
+// Session returns the session the loop drives.
+func (l *GameLoop) Session() *GameSession {
+	return l.session
+}
+
+// Step processes one frame. At most one phase change happens per frame, so a key that is
+// reported together with another (or held across frames by a device source) cannot skip a phase.
+// The error is non-nil only if the transition table rejects a move.
+func (l *GameLoop) Step() error {
+	actions := l.input.Actions()
+	switch l.phase.Current() {
+	case PhaseInput:
+		for _, a := range actions {
+			if a.Kind == ActionDigit && l.session.Granularity() == InputDigits {
+				return l.Submit(InputDigits.Value(a.Digit))
+			}
+		}
+	case PhaseConfirm:
+		for _, a := range actions {
+			switch a.Kind {
+			case ActionConfirm:
+				return l.phase.Transition(PhaseBattle)
+			case ActionCancel:
+				l.session.CancelInput()
+				return l.phase.Transition(PhaseInput)
+			}
+		}
+	case PhaseBattle:
+		return l.battle()
+	case PhaseEnd:
+		for _, a := range actions {
+			if a.Kind == ActionRetry {
+				l.session.Retry()
+				return l.phase.Transition(PhaseInput)
+			}
+		}
+	}
+	return nil
+}
+
+// Submit sets the input for the current turn and moves to the confirm phase.
+// Front ends with typed inputs (finer granularities) call it directly.
+func (l *GameLoop) Submit(x float64) error {
+	if l.phase.Current() != PhaseInput {
+		return &TransitionError{From: l.phase.Current(), To: PhaseConfirm}
+	}
+	if err := l.session.SubmitInput(x); err != nil {
+		return err
+	}
+	return l.phase.Transition(PhaseConfirm)
+}
+
+// battle: 確定した入力で戦い、最終戦なら end、そうでなければ次の入力へ
+func (l *GameLoop) battle() error {
+	turn, err := l.session.Confirm()
+	if err != nil {
+		return l.phase.Transition(PhaseInput)
+	}
+	for _, fn := range l.onTurn {
+		fn(turn)
+	}
+	if l.session.Over() {
+		return l.phase.Transition(PhaseEnd)
+	}
+	return l.phase.Transition(PhaseInput)
+}
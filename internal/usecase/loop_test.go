@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// newTestLoop: smallBankConfig（3 戦）のゲームを input フェーズから始めるループ
+func newTestLoop(granularity InputGranularity, src InputSource) (*GameLoop, *PhaseMachine) {
+	config := smallBankConfig()
+	player, enemy := config.NewCombatants()
+	session := NewGameSession(13, config.BattleMax, player, enemy, granularity)
+	phase := NewPhaseMachine(PhaseMenu, GameTransitions())
+	if err := phase.Transition(PhaseInput); err != nil {
+		panic(err)
+	}
+	return NewGameLoop(session, phase, src), phase
+}
+
+// turnFrames: 1 戦分の操作（数字・決定・バトル処理のフレーム）
+func turnFrames(digit int) [][]Action {
+	return [][]Action{{DigitAction(digit)}, {ConfirmAction}, nil}
+}
+
+func script(turns ...[][]Action) *ScriptedInput {
+	var frames [][]Action
+	for _, t := range turns {
+		frames = append(frames, t...)
+	}
+	return NewScriptedInput(frames...)
+}
+
+func TestGameLoop_Script(t *testing.T) {
+	tests := []struct {
+		name        string
+		granularity InputGranularity
+		start       Phase             // transitions があれば、このフェーズから始める（無ければ input）
+		transitions map[Phase][]Phase // nil なら GameTransitions
+		src         *ScriptedInput
+		wantInputs  []float64
+		wantPhase   Phase
+		wantTurns   int  // OnTurn の呼び出し回数（リトライ前のバトルを含む）
+		wantErr     bool // Step が TransitionError を返す
+	}{
+		{"full game", InputDigits, 0, nil, script(turnFrames(0), turnFrames(9), turnFrames(5)), []float64{0, 1, 5.0 / 9}, PhaseEnd, 3, false},
+		{"partial game", InputDigits, 0, nil, script(turnFrames(2)), []float64{2.0 / 9}, PhaseInput, 1, false},
+		{"cancel and re-input", InputDigits, 0, nil, script([][]Action{{DigitAction(1)}, {CancelAction}, {DigitAction(4)}, {ConfirmAction}, nil}),
+			[]float64{4.0 / 9}, PhaseInput, 1, false},
+		// 同じフレームの数字と決定では確認を飛ばさない
+		{"digit and confirm in one frame", InputDigits, 0, nil, script([][]Action{{DigitAction(3), ConfirmAction}}), []float64{}, PhaseConfirm, 0, false},
+		// 入力フェーズの決定・取り消し・リトライは無視
+		{"ignored actions", InputDigits, 0, nil, script([][]Action{{ConfirmAction, CancelAction, RetryAction}}), []float64{}, PhaseInput, 0, false},
+		{"confirm ignores digits", InputDigits, 0, nil, script([][]Action{{DigitAction(3)}, {DigitAction(4)}, {ConfirmAction}, nil}), []float64{3.0 / 9}, PhaseInput, 1, false},
+		{"retry after the game", InputDigits, 0, nil, script(turnFrames(0), turnFrames(0), turnFrames(0), [][]Action{{DigitAction(1)}, {RetryAction}}, turnFrames(7)),
+			[]float64{7.0 / 9}, PhaseInput, 4, false},
+		// 連続入力では数字キーは無視される（入力は Submit で渡す）
+		{"digits ignored with continuous input", InputContinuous, 0, nil, ScriptActions(DigitAction(3)), []float64{}, PhaseInput, 0, false},
+		// 遷移表が拒否する遷移はエラーとして返す
+		{"rejected transition", InputDigits, PhaseInput, map[Phase][]Phase{}, ScriptActions(DigitAction(1)), []float64{}, PhaseInput, 0, true},
+		// 入力が無いままのバトルは入力フェーズへ戻す
+		{"battle without input", InputDigits, PhaseBattle, GameTransitions(), NewScriptedInput(nil), []float64{}, PhaseInput, 0, false},
+		// menu など他のフェーズでは何もしない
+		{"menu is left alone", InputDigits, PhaseMenu, GameTransitions(), ScriptActions(DigitAction(1)), []float64{}, PhaseMenu, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loop, phase := newTestLoop(tt.granularity, tt.src)
+			if tt.transitions != nil {
+				phase = NewPhaseMachine(tt.start, tt.transitions)
+				loop.phase = phase
+			}
+			var turns []TurnRecord
+			loop.OnTurn(func(turn TurnRecord) {
+				turns = append(turns, turn)
+				if phase.Current() != PhaseBattle { // フェーズが進む前に呼ばれる
+					t.Errorf("phase during OnTurn = %s, want battle", phase.Current())
+				}
+			})
+			var err error
+			for err == nil && !tt.src.Done() {
+				err = loop.Step()
+			}
+			var te *TransitionError
+			if tt.wantErr != errors.As(err, &te) || !tt.wantErr && err != nil {
+				t.Fatalf("Step error = %v, want TransitionError %v", err, tt.wantErr)
+			}
+			if got := loop.Session().Inputs(); !reflect.DeepEqual(got, tt.wantInputs) {
+				t.Errorf("Inputs = %v, want %v", got, tt.wantInputs)
+			}
+			if phase.Current() != tt.wantPhase {
+				t.Errorf("phase = %s, want %s", phase.Current(), tt.wantPhase)
+			}
+			// OnTurn は各バトルの後に呼ばれ、最後の記録は今のゲームの結果と一致する
+			got := loop.Session().Result().Turns
+			if len(turns) != tt.wantTurns || len(turns) < len(got) || !reflect.DeepEqual(turns[len(turns)-len(got):], got) {
+				t.Errorf("OnTurn records %+v, want %d ending with %+v", turns, tt.wantTurns, got)
+			}
+		})
+	}
+}
+
+func TestGameLoop_Submit(t *testing.T) {
+	tests := []struct {
+		name      string
+		x         float64
+		confirm   bool // 先に Submit して確認フェーズにしておく
+		wantErr   bool
+		wantPhase Phase
+	}{
+		{"continuous input", 0.3, false, false, PhaseConfirm},
+		{"out of range", 2, false, true, PhaseInput},
+		{"not in input phase", 0.3, true, true, PhaseConfirm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loop, phase := newTestLoop(InputContinuous, ScriptActions())
+			if tt.confirm {
+				if err := loop.Submit(0.5); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := loop.Submit(tt.x)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Submit error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if phase.Current() != tt.wantPhase {
+				t.Errorf("phase = %s, want %s", phase.Current(), tt.wantPhase)
+			}
+		})
+	}
+}
+
+func TestNewGameLoop_Panics(t *testing.T) {
+	loop, phase := newTestLoop(InputDigits, ScriptActions())
+	tests := []struct {
+		name    string
+		session *GameSession
+		phase   *PhaseMachine
+		input   InputSource
+	}{
+		{"nil session", nil, phase, ScriptActions()},
+		{"nil phase", loop.Session(), nil, ScriptActions()},
+		{"nil input", loop.Session(), phase, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("NewGameLoop should panic")
+				}
+			}()
+			NewGameLoop(tt.session, tt.phase, tt.input)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import "fmt"
+
+// Phase is a screen state of the game front end.
+type Phase int
+
+const (
+	PhaseMenu    Phase = iota // seed 入力・タイトル
+	PhaseLoading              // seed の探索・検証中
+	PhaseInput                // 入力待ち
+	PhaseConfirm              // 入力の確認
+	PhaseBattle               // バトル処理
+	PhaseEnd                  // 最終戦の後
+	PhaseReveal               // 敗北後の勝ち筋表示
+)
+
+var phaseNames = [...]string{"menu", "loading", "input", "confirm", "battle", "end", "reveal"}
+
+func (p Phase) String() string {
+	if p >= 0 && int(p) < len(phaseNames) {
+		return phaseNames[p]
+	}
+	return fmt.Sprintf("Phase(%d)", int(p))
+}
+
+// GameTransitions returns the legal moves between the phases of the game.
+func GameTransitions() map[Phase][]Phase {
+	return map[Phase][]Phase{
+		PhaseMenu:    {PhaseLoading, PhaseInput},                         // 探索を始める / 検証なしの seed・bank の seed で始める
+		PhaseLoading: {PhaseMenu, PhaseInput, PhaseEnd},                  // キャンセル・失敗 / 探索完了 / 勝ち筋の探索から終了画面へ戻る
+		PhaseInput:   {PhaseConfirm},                                     // 入力を決める
+		PhaseConfirm: {PhaseBattle, PhaseInput},                          // 確定 / 再入力
+		PhaseBattle:  {PhaseInput, PhaseEnd},                             // 次のターン / 最終戦
+		PhaseEnd:     {PhaseInput, PhaseLoading, PhaseMenu, PhaseReveal}, // リトライ・bank の seed / 新しい seed の探索 / メニュー / 勝ち筋
+		PhaseReveal:  {PhaseEnd},                                         // 終了画面へ戻る
+	}
+}
+
+// TransitionError reports a phase change that the transition table does not allow.
+type TransitionError struct {
+	From, To Phase
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("illegal phase transition: %s -> %s", e.From, e.To)
+}
+
+// PhaseMachine holds the current phase and only moves along a transition table.
+// Hooks run on every legal transition: exit hooks of the old phase, then enter hooks of the new one.
+type PhaseMachine struct {
+	current Phase
+	allowed map[Phase]map[Phase]bool
+	enter   map[Phase][]func(from Phase)
+	exit    map[Phase][]func(to Phase)
+}
+
+// NewPhaseMachine starts in initial without running its enter hooks.
+// transitions maps each phase to the phases it may move to; a self-transition must be listed explicitly.
+func NewPhaseMachine(initial Phase, transitions map[Phase][]Phase) *PhaseMachine {
+	allowed := make(map[Phase]map[Phase]bool, len(transitions))
+	for from, tos := range transitions {
+		allowed[from] = make(map[Phase]bool, len(tos))
+		for _, to := range tos {
+			allowed[from][to] = true
+		}
+	}
+	return &PhaseMachine{
+		current: initial,
+		allowed: allowed,
+		enter:   make(map[Phase][]func(Phase)),
+		exit:    make(map[Phase][]func(Phase)),
+	}
+}
+
+// Current returns the current phase.
+func (m *PhaseMachine) Current() Phase {
+	return m.current
+}
+
+// Can reports whether the machine may move from the current phase to to.
+func (m *PhaseMachine) Can(to Phase) bool {
+	return m.allowed[m.current][to]
+}
+
+// Transition moves to to and runs the hooks, or returns a *TransitionError and stays put.
+// The current phase is already to while enter hooks run, so a hook may transition again.
+func (m *PhaseMachine) Transition(to Phase) error {
+	from := m.current
+	if !m.Can(to) {
+		return &TransitionError{From: from, To: to}
+	}
+	for _, fn := range m.exit[from] {
+		fn(to)
+	}
+	m.current = to
+	for _, fn := range m.enter[to] {
+		fn(from)
+	}
+	return nil
+}
+
+// OnEnter registers fn to run whenever the machine enters p. fn receives the previous phase.
+func (m *PhaseMachine) OnEnter(p Phase, fn func(from Phase)) {
+	m.enter[p] = append(m.enter[p], fn)
+}
+
+// OnExit registers fn to run whenever the machine leaves p. fn receives the next phase.
+func (m *PhaseMachine) OnExit(p Phase, fn func(to Phase)) {
+	m.exit[p] = append(m.exit[p], fn)
+}
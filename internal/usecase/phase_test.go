@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPhase_String(t *testing.T) {
+	tests := []struct {
+		p    Phase
+		want string
+	}{
+		{PhaseMenu, "menu"},
+		{PhaseLoading, "loading"},
+		{PhaseInput, "input"},
+		{PhaseConfirm, "confirm"},
+		{PhaseBattle, "battle"},
+		{PhaseEnd, "end"},
+		{PhaseReveal, "reveal"},
+		{Phase(-1), "Phase(-1)"},
+		{Phase(99), "Phase(99)"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("Phase(%d).String() = %q, want %q", int(tt.p), got, tt.want)
+		}
+	}
+}
+
+func TestPhaseMachine_GameTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []Phase // 初期状態 menu からの遷移
+		illegal Phase   // path の後で拒否される遷移
+	}{
+		{"menu to battle", nil, PhaseBattle},
+		{"menu to itself", nil, PhaseMenu},
+		{"input skips confirm", []Phase{PhaseInput}, PhaseBattle},
+		{"input to end", []Phase{PhaseInput}, PhaseEnd},
+		{"battle back to confirm", []Phase{PhaseInput, PhaseConfirm, PhaseBattle}, PhaseConfirm},
+		{"reveal from battle", []Phase{PhaseInput, PhaseConfirm, PhaseBattle}, PhaseReveal},
+		{"reveal to input", []Phase{PhaseLoading, PhaseInput, PhaseConfirm, PhaseBattle, PhaseEnd, PhaseReveal}, PhaseInput},
+		{"loading to reveal", []Phase{PhaseLoading}, PhaseReveal},
+		{"winning line search back to end", []Phase{PhaseInput, PhaseConfirm, PhaseBattle, PhaseEnd, PhaseLoading, PhaseEnd}, PhaseConfirm},
+		{"end to confirm", []Phase{PhaseInput, PhaseConfirm, PhaseBattle, PhaseEnd}, PhaseConfirm},
+		{"after retry", []Phase{PhaseInput, PhaseConfirm, PhaseBattle, PhaseEnd, PhaseInput, PhaseConfirm, PhaseInput}, PhaseEnd},
+		{"back to menu", []Phase{PhaseInput, PhaseConfirm, PhaseBattle, PhaseEnd, PhaseMenu, PhaseLoading, PhaseMenu}, PhaseReveal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewPhaseMachine(PhaseMenu, GameTransitions())
+			for _, p := range tt.path {
+				if err := m.Transition(p); err != nil {
+					t.Fatalf("Transition(%s): %v", p, err)
+				}
+			}
+			last := m.Current()
+			if m.Can(tt.illegal) {
+				t.Errorf("Can(%s) from %s = true", tt.illegal, last)
+			}
+			err := m.Transition(tt.illegal)
+			var te *TransitionError
+			if !errors.As(err, &te) || te.From != last || te.To != tt.illegal {
+				t.Fatalf("Transition(%s) error = %v, want TransitionError from %s", tt.illegal, err, last)
+			}
+			if err.Error() != "illegal phase transition: "+last.String()+" -> "+tt.illegal.String() {
+				t.Errorf("Error() = %q", err.Error())
+			}
+			if m.Current() != last {
+				t.Errorf("Current = %s after a rejected transition, want %s", m.Current(), last)
+			}
+		})
+	}
+}
+
+func TestPhaseMachine_Hooks(t *testing.T) {
+	selfOnly := map[Phase][]Phase{PhaseInput: {PhaseInput}}
+	tests := []struct {
+		name        string
+		initial     Phase
+		transitions map[Phase][]Phase
+		chain       map[Phase]Phase // 入ったときに enter フックの中から続けて遷移する先
+		steps       []Phase         // 順に Transition する（拒否されたものは無視する）
+		wantCalls   []string
+		wantCurrent Phase
+	}{
+		{"no transition runs no hooks", PhaseMenu, GameTransitions(), nil, nil, nil, PhaseMenu},
+		{"exit then enter, hooks in registration order", PhaseMenu, GameTransitions(), nil,
+			[]Phase{PhaseLoading, PhaseInput},
+			[]string{"exit menu -> loading", "enter loading <- menu", "enter loading 2", "exit loading -> input", "enter input <- loading"},
+			PhaseInput},
+		{"enter hook transitions again", PhaseInput, GameTransitions(), map[Phase]Phase{PhaseBattle: PhaseEnd},
+			[]Phase{PhaseConfirm, PhaseBattle},
+			[]string{"exit input -> confirm", "enter confirm <- input", "exit confirm -> battle", "enter battle <- confirm", "exit battle -> end", "enter end <- battle"},
+			PhaseEnd},
+		{"rejected transition runs no hooks", PhaseMenu, GameTransitions(), nil,
+			[]Phase{PhaseBattle, PhaseInput, PhaseEnd},
+			[]string{"exit menu -> input", "enter input <- menu"},
+			PhaseInput},
+		{"self transition", PhaseInput, selfOnly, nil,
+			[]Phase{PhaseInput},
+			[]string{"exit input -> input", "enter input <- input"},
+			PhaseInput},
+		{"phase missing from the table", PhaseInput, selfOnly, nil, []Phase{PhaseMenu}, nil, PhaseInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewPhaseMachine(tt.initial, tt.transitions)
+			var calls []string
+			for p := PhaseMenu; p <= PhaseReveal; p++ {
+				m.OnExit(p, func(to Phase) { calls = append(calls, "exit "+p.String()+" -> "+to.String()) })
+				m.OnEnter(p, func(from Phase) {
+					calls = append(calls, "enter "+p.String()+" <- "+from.String())
+					if m.Current() != p {
+						t.Errorf("Current = %s in the enter hook of %s", m.Current(), p)
+					}
+					if next, ok := tt.chain[p]; ok {
+						if err := m.Transition(next); err != nil {
+							t.Error(err)
+						}
+					}
+				})
+			}
+			m.OnEnter(PhaseLoading, func(Phase) { calls = append(calls, "enter loading 2") })
+			for _, p := range tt.steps {
+				_ = m.Transition(p)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("calls = %q, want %q", calls, tt.wantCalls)
+			}
+			if m.Current() != tt.wantCurrent {
+				t.Errorf("Current = %s, want %s", m.Current(), tt.wantCurrent)
+			}
+		})
+	}
+}
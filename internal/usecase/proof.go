@@ -0,0 +1,373 @@
+package usecase
+
+import (
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/logic"
+	"encoding/binary"
+	"math"
+)
+
+// ProofStrategy selects the tree search used by the proof phase.
+type ProofStrategy int
+
+const (
+	ProofMCTS ProofStrategy = iota // UCT による Monte-Carlo Tree Search（既定）
+	ProofDFS                       // 幅 3 のランダム DFS（比較用）
+)
+
+// String returns the display name of the strategy.
+func (s ProofStrategy) String() string {
+	if s == ProofDFS {
+		return "DFS"
+	}
+	return "MCTS"
+}
+
+// RolloutPolicy picks the next input (0-9) during an MCTS rollout. path holds the inputs so far.
+type RolloutPolicy func(rng logic.RandomSource, path []int) int
+
+// UniformRollout picks every input with equal probability.
+func UniformRollout(rng logic.RandomSource, path []int) int {
+	return rng.Intn(10)
+}
+
+// RepeatRollout repeats the previous input half of the time, otherwise picks uniformly.
+// 同じ入力を続けると行列の特定成分が伸び続けるため、一様乱数では届きにくい極端な局面を試せる
+func RepeatRollout(rng logic.RandomSource, path []int) int {
+	if len(path) > 0 && rng.Float64() < 0.5 {
+		return path[len(path)-1]
+	}
+	return rng.Intn(10)
+}
+
+// DefaultExploration is the UCT exploration constant used when ProofOptions.Exploration is 0.
+var DefaultExploration = math.Sqrt2
+
+// ProofOptions tunes the proof phase. The zero value uses UCT MCTS with the defaults.
+// Strategy, Exploration and Rollout only apply to InputDigits; finer granularities always use coordinate search.
+type ProofOptions struct {
+	Strategy    ProofStrategy
+	Exploration float64       // UCT の探索定数（0 以下なら DefaultExploration）
+	Iterations  int           // MCTS の反復数・DFS のノード数・座標探索の評価回数の上限（0 以下なら DFS 1000×サイズ²、それ以外 200×サイズ²）
+	Rollout     RolloutPolicy // MCTS のロールアウト方策（nil なら UniformRollout）
+}
+
+// proofResult: ProofPhase の結果
+type proofResult struct {
+	ok           bool
+	playerPath   []int // 10 キーで探索した場合のみ
+	enemyPath    []int
+	playerInputs []float64
+	enemyInputs  []float64
+	playerLeaves int
+	enemyLeaves  int
+	nodes        int
+	// 勝敗が分岐しうる最も深いターン（勝ちパスと負けパスの共通接頭辞長 + 1）
+	decisiveDepth int
+	// プレイヤー勝利パスに現れた初手の区分（InputGranularity.firstMoveClass）
+	playerFirstMoves map[int]bool
+	// 見つかったプレイヤー勝利パス全て（制約を満たすパスを探すのに使う）
+	playerWinPaths [][]float64
+}
+
+// proofPhase: 設定された戦略で双方の勝ちパスを探す（10 キー以外の粒度では座標探索）
+func (e *seedEvaluator) proofPhase(rule *domain.RuleMatrix) proofResult {
+	switch {
+	case e.input != InputDigits:
+		return e.coordinateProof(rule)
+	case e.proof.Strategy == ProofDFS:
+		return e.dfsProof(rule)
+	}
+	return e.mctsProof(rule)
+}
+
+// newProofResult: 見つかった 10 キーの末端パスから proofResult を組み立てる
+func (e *seedEvaluator) newProofResult(playerPaths, enemyPaths [][]int, nodes int) proofResult {
+	toInputs := func(paths [][]int) [][]float64 {
+		inputs := make([][]float64, len(paths))
+		for i, p := range paths {
+			inputs[i] = digitInputs(p)
+		}
+		return inputs
+	}
+	result := e.newInputProofResult(toInputs(playerPaths), toInputs(enemyPaths), nodes)
+	if result.ok {
+		result.playerPath = digitPath(result.playerInputs)
+		result.enemyPath = digitPath(result.enemyInputs)
+	}
+	return result
+}
+
+// newInputProofResult: 見つかった末端の入力列から proofResult を組み立てる
+// 双方に少なくとも 1 パスずつあれば ok とし、代表パスをランダムに 1 本ずつ選ぶ
+func (e *seedEvaluator) newInputProofResult(playerPaths, enemyPaths [][]float64, nodes int) proofResult {
+	result := proofResult{
+		playerLeaves:     len(playerPaths),
+		enemyLeaves:      len(enemyPaths),
+		nodes:            nodes,
+		decisiveDepth:    decisiveDepth(playerPaths, enemyPaths),
+		playerFirstMoves: map[int]bool{},
+		playerWinPaths:   playerPaths,
+	}
+	for _, p := range playerPaths {
+		result.playerFirstMoves[e.input.firstMoveClass(p[0])] = true
+	}
+	if len(playerPaths) == 0 || len(enemyPaths) == 0 {
+		return result
+	}
+	result.ok = true
+	result.playerInputs = playerPaths[e.rng.Intn(len(playerPaths))]
+	result.enemyInputs = enemyPaths[e.rng.Intn(len(enemyPaths))]
+	return result
+}
+
+// dfsProof: DFS＋ビーム幅＋分岐シャッフルで多様な勝ちパスを探索
+func (e *seedEvaluator) dfsProof(rule *domain.RuleMatrix) proofResult {
+	type node struct {
+		depth  int
+		inputs []int
+	}
+
+	var (
+		playerPaths [][]int
+		enemyPaths  [][]int
+		nodes       int
+	)
+
+	var dfs func(n node)
+	dfs = func(n node) {
+		if nodes >= e.proofBudget || e.cancelled() {
+			return
+		}
+		nodes++
+
+		// 末端まで到達したら勝敗を判定
+		if n.depth == e.battleMax {
+			if e.playPath(rule, n.inputs) {
+				// プレイヤー勝利パス
+				playerPaths = append(playerPaths, append([]int(nil), n.inputs...))
+			} else {
+				// 敵勝利パス
+				enemyPaths = append(enemyPaths, append([]int(nil), n.inputs...))
+			}
+			return
+		}
+
+		// 0.0〜1.0 を 1/9 刻み 10 通り用意し、毎ノードでシャッフル
+		choices := make([]int, 10)
+		for i := 0; i < 10; i++ {
+			choices[i] = i
+		}
+		e.rng.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+
+		// ランダムに dfsWidth 本を採用（幅制限）
+		if len(choices) > e.dfsWidth {
+			choices = choices[:e.dfsWidth]
+		}
+
+		for _, v := range choices {
+			dfs(node{depth: n.depth + 1, inputs: append(append([]int(nil), n.inputs...), v)})
+			if nodes >= e.proofBudget {
+				return
+			}
+		}
+	}
+
+	dfs(node{depth: 0, inputs: []int{}})
+	return e.newProofResult(playerPaths, enemyPaths, nodes)
+}
+
+// mctsNode: 探索木のノード。そのターンまで進めた player / enemy の状態を保持し、
+// 子の展開やロールアウトは Reset からやり直さずこの状態の複製から続ける
+type mctsNode struct {
+	parent   *mctsNode
+	children []*mctsNode
+	untried  []int // 未展開の入力（シャッフル済み）
+	path     []int // 根からこのノードまでの入力列
+	visits   int
+	wins     float64 // プレイヤー勝利の回数
+	player   *domain.Player
+	enemy    *domain.Enemy
+	win      bool // 末端ノードの場合の最終戦の勝敗
+}
+
+// mctsProof: UCT でプレイヤーの勝ちに寄せながら探索し、ロールアウトで到達した末端を勝ち / 負けパスとして集める
+func (e *seedEvaluator) mctsProof(rule *domain.RuleMatrix) proofResult {
+	e.player.Reset()
+	e.enemy.Reset()
+	root := e.newMCTSNode(nil, nil, e.player.Clone(), e.enemy.Clone(), false)
+
+	var (
+		playerPaths [][]int
+		enemyPaths  [][]int
+		seen        = map[string]bool{} // 記録済みの末端パス
+		nodes       = 1
+	)
+	record := func(path []int, win bool) {
+		key := pathKey(path)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if win {
+			playerPaths = append(playerPaths, path)
+		} else {
+			enemyPaths = append(enemyPaths, path)
+		}
+	}
+
+	for it := 0; it < e.proofBudget && !e.cancelled(); it++ {
+		// Selection: 展開し尽くしたノードは UCT 値が最大の子へ進む
+		n := root
+		for len(n.untried) == 0 && len(n.children) > 0 {
+			n = e.selectUCT(n)
+		}
+
+		// Expansion: 未展開の入力を 1 つ選び、親の状態から 1 ターンだけ進める
+		if len(n.untried) > 0 {
+			input := n.untried[len(n.untried)-1]
+			n.untried = n.untried[:len(n.untried)-1]
+			player, enemy := n.player.Clone(), n.enemy.Clone()
+			_, win := NewBattleService(player, enemy, rule).DoBattleTurn(float64(input)/9, len(n.path))
+			child := e.newMCTSNode(n, append(append([]int(nil), n.path...), input), player, enemy, win)
+			n.children = append(n.children, child)
+			n = child
+			nodes++
+		}
+
+		// Simulation: 末端ならその勝敗、そうでなければ状態の複製からロールアウト
+		win := n.win
+		if len(n.path) < e.battleMax {
+			path := append([]int(nil), n.path...)
+			player, enemy := n.player.Clone(), n.enemy.Clone()
+			service := NewBattleService(player, enemy, rule)
+			for battle := len(path); battle < e.battleMax; battle++ {
+				input := e.rollout(e.rng, path)
+				path = append(path, input)
+				_, win = service.DoBattleTurn(float64(input)/9, battle)
+			}
+			record(path, win)
+		} else {
+			record(n.path, win)
+		}
+
+		// Backpropagation
+		for ; n != nil; n = n.parent {
+			n.visits++
+			if win {
+				n.wins++
+			}
+		}
+	}
+	return e.newProofResult(playerPaths, enemyPaths, nodes)
+}
+
+// newMCTSNode: 末端でなければ 10 通りの入力をシャッフルして未展開として持つ
+func (e *seedEvaluator) newMCTSNode(parent *mctsNode, path []int, player *domain.Player, enemy *domain.Enemy, win bool) *mctsNode {
+	n := &mctsNode{parent: parent, path: path, player: player, enemy: enemy, win: win}
+	if len(path) < e.battleMax {
+		n.untried = make([]int, 10)
+		for i := range n.untried {
+			n.untried[i] = i
+		}
+		e.rng.Shuffle(len(n.untried), func(i, j int) { n.untried[i], n.untried[j] = n.untried[j], n.untried[i] })
+	}
+	return n
+}
+
+// selectUCT: 勝率＋探索ボーナスが最大の子を返す（同値なら先に展開した子）
+func (e *seedEvaluator) selectUCT(n *mctsNode) *mctsNode {
+	best, bestValue := n.children[0], math.Inf(-1)
+	logN := math.Log(float64(n.visits))
+	for _, c := range n.children {
+		value := c.wins/float64(c.visits) + e.exploration*math.Sqrt(logN/float64(c.visits))
+		if value > bestValue {
+			best, bestValue = c, value
+		}
+	}
+	return best
+}
+
+// pathKey: 入力列（各 0〜9）を重複判定用の文字列にする
+func pathKey(path []int) string {
+	b := make([]byte, len(path))
+	for i, v := range path {
+		b[i] = byte('0' + v)
+	}
+	return string(b)
+}
+
+// coordinateProof: 連続的な入力空間 [0, 1]^battleMax を座標探索で探す（離散的な分岐は使わない）
+// ランダムな初期点から、最終戦の結果をプレイヤー側は最大化・敵側は最小化するよう 1 座標ずつ ±刻み幅 を試し、
+// 改善しなくなったら刻み幅を半分にする。再出発ごとに向きを交互に変え、評価した入力列を全て末端として記録する
+func (e *seedEvaluator) coordinateProof(rule *domain.RuleMatrix) proofResult {
+	var (
+		playerPaths [][]float64
+		enemyPaths  [][]float64
+		seen        = map[string]bool{}
+		evals       int
+	)
+	eval := func(x []float64) float64 {
+		evals++
+		result, win := e.playInputs(rule, x)
+		if key := inputsKey(x); !seen[key] {
+			seen[key] = true
+			if win {
+				playerPaths = append(playerPaths, append([]float64(nil), x...))
+			} else {
+				enemyPaths = append(enemyPaths, append([]float64(nil), x...))
+			}
+		}
+		return result
+	}
+
+	order := make([]int, e.battleMax)
+	for i := range order {
+		order[i] = i
+	}
+	for restart := 0; evals < e.proofBudget && !e.cancelled(); restart++ {
+		// 偶数回目はプレイヤーの勝ち（結果 > 0）、奇数回目は敵の勝ちを目指す
+		sign := 1.0
+		if restart%2 == 1 {
+			sign = -1
+		}
+		x := make([]float64, e.battleMax)
+		for i := range x {
+			x[i] = e.input.random(e.rng)
+		}
+		fx := sign * eval(x)
+		for h := 0.25; fx <= 0 && evals < e.proofBudget && !e.cancelled(); {
+			improved := false
+			e.rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+			for _, t := range order {
+				for _, d := range []float64{h, -h} {
+					y := append([]float64(nil), x...)
+					y[t] = e.input.Quantize(x[t] + d)
+					if y[t] == x[t] || evals >= e.proofBudget {
+						continue
+					}
+					if fy := sign * eval(y); fy > fx {
+						x, fx, improved = y, fy, true
+						break
+					}
+				}
+			}
+			if !improved {
+				if h <= e.input.minStep() {
+					break
+				}
+				h = math.Max(h/2, e.input.minStep())
+			}
+		}
+	}
+	return e.newInputProofResult(playerPaths, enemyPaths, evals)
+}
+
+// inputsKey: 入力列を重複判定用の文字列にする
+func inputsKey(inputs []float64) string {
+	b := make([]byte, 0, 8*len(inputs))
+	for _, x := range inputs {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(x))
+	}
+	return string(b)
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/logic"
+)
+
+func newProofPair() (*domain.Player, *domain.Enemy) {
+	return domain.NewPlayer(domain.NewMatrix([][]float64{{2, 0}, {0, 2}}), 0.5),
+		domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 2}, {2, 0}}), 0.5)
+}
+
+func TestProofStrategy_String(t *testing.T) {
+	tests := []struct {
+		s    ProofStrategy
+		want string
+	}{
+		{ProofMCTS, "MCTS"},
+		{ProofDFS, "DFS"},
+	}
+	for _, tt := range tests {
+		if got := tt.s.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestRolloutPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RolloutPolicy
+		path   []int
+		repeat bool // 直前の入力を繰り返すことがあるか
+	}{
+		{"uniform", UniformRollout, []int{3}, false},
+		{"repeat", RepeatRollout, []int{3}, true},
+		{"repeat with empty path", RepeatRollout, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := logic.NewSeedManagerWithFixedValue(1)
+			repeats := 0
+			for i := 0; i < 1000; i++ {
+				v := tt.policy(rng, tt.path)
+				if v < 0 || v > 9 {
+					t.Fatalf("input %d out of range", v)
+				}
+				if len(tt.path) > 0 && v == tt.path[len(tt.path)-1] {
+					repeats++
+				}
+			}
+			// 一様なら約 100 回、RepeatRollout なら約 550 回
+			if got := repeats > 300; got != tt.repeat {
+				t.Errorf("repeats = %d, want repeat bias %v", repeats, tt.repeat)
+			}
+		})
+	}
+}
+
+func TestPathKey(t *testing.T) {
+	tests := []struct {
+		path []int
+		want string
+	}{
+		{nil, ""},
+		{[]int{0, 9, 5}, "095"},
+	}
+	for _, tt := range tests {
+		if got := pathKey(tt.path); got != tt.want {
+			t.Errorf("pathKey(%v) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestInputsKey(t *testing.T) {
+	tests := []struct {
+		a, b []float64
+		same bool
+	}{
+		{nil, []float64{}, true},
+		{[]float64{0.5, 1}, []float64{0.5, 1}, true},
+		{[]float64{0.5, 1}, []float64{1, 0.5}, false},
+		{[]float64{0.3}, []float64{math.Nextafter(0.3, 1)}, false},
+	}
+	for _, tt := range tests {
+		if got := inputsKey(tt.a) == inputsKey(tt.b); got != tt.same {
+			t.Errorf("inputsKey(%v) == inputsKey(%v) is %v, want %v", tt.a, tt.b, got, tt.same)
+		}
+	}
+}
+
+func TestMCTSProof_Exhaustive(t *testing.T) {
+	pair3 := [2][][]float64{{{2, 0, 0}, {0, 2, 0}, {0, 0, 2}}, {{0, 0, 2}, {0, 2, 0}, {2, 0, 0}}}
+	tests := []struct {
+		name       string
+		matrices   [2][][]float64 // 空なら newProofPair の 2x2
+		seed       int64
+		battleMax  int
+		iterations int
+	}{
+		// battleMax 1 なら末端は 10 本しかなく、予算が十分なら全て 1 回ずつ記録される
+		{"2x2 one battle", [2][][]float64{}, 1, 1, 50},
+		{"2x2 one battle other seed", [2][][]float64{}, 4, 1, 50},
+		{"3x3 one battle", pair3, FallbackSeed, 1, 50},
+		{"2x2 two battles", [2][][]float64{}, 1, 2, 500},
+		{"3x3 two battles", pair3, FallbackSeed, 2, 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := newProofPair()
+			if tt.matrices[0] != nil {
+				player = domain.NewPlayer(domain.NewMatrix(tt.matrices[0]), 0.5)
+				enemy = domain.NewEnemy("E", domain.NewMatrix(tt.matrices[1]), 0.5)
+			}
+			e := newSeedEvaluator(tt.battleMax, player, enemy, DefaultSeedCriteria(), SeedSearchOptions{Proof: ProofOptions{Iterations: tt.iterations}})
+			e.rng = logic.NewSeedManagerWithFixedValue(1)
+			rule := NewRuleForSeed(tt.seed, e.size)
+			r := e.mctsProof(rule)
+			leaves, nodes := 1, 1
+			for i := 0; i < tt.battleMax; i++ {
+				leaves *= 10
+				nodes += leaves
+			}
+			if r.playerLeaves+r.enemyLeaves != leaves || r.nodes != nodes {
+				t.Errorf("leaves = %d+%d, nodes = %d, want %d leaves and %d nodes", r.playerLeaves, r.enemyLeaves, r.nodes, leaves, nodes)
+			}
+			// 全入力列を再生した勝ち数と一致する
+			wins := 0
+			path := make([]int, tt.battleMax)
+			for n := 0; n < leaves; n++ {
+				for i, k := 0, n; i < tt.battleMax; i, k = i+1, k/10 {
+					path[tt.battleMax-1-i] = k % 10
+				}
+				if e.playPath(rule, path) {
+					wins++
+				}
+			}
+			if r.playerLeaves != wins {
+				t.Errorf("playerLeaves = %d, want %d (by replay)", r.playerLeaves, wins)
+			}
+		})
+	}
+}
+
+func TestProofPhase_PathsReplay(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ProofOptions
+	}{
+		{"mcts default", ProofOptions{}},
+		{"mcts repeat rollout", ProofOptions{Rollout: RepeatRollout, Exploration: 0.5}},
+		{"dfs", ProofOptions{Strategy: ProofDFS}},
+	}
+	player, enemy := newProofPair()
+	seed, err := FindValidSeed(t.Context(), 5, player, enemy, logic.NewSeedManagerWithFixedValue(2), SeedSearchOptions{})
+	if err != nil {
+		t.Fatalf("FindValidSeed error: %v", err)
+	}
+	rule := NewRuleForSeed(seed.Seed, 2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := func() proofResult {
+				e := newSeedEvaluator(5, player, enemy, DefaultSeedCriteria(), SeedSearchOptions{Proof: tt.opts})
+				e.rng = logic.NewSeedManagerWithFixedValue(seed.Seed)
+				return e.proofPhase(rule)
+			}
+			r := run()
+			if !r.ok {
+				t.Fatalf("proof failed: %+v", r)
+			}
+			// 状態のスナップショットから進めた結果が Reset からの再生と一致する
+			e := newSeedEvaluator(5, player, enemy, DefaultSeedCriteria(), SeedSearchOptions{Proof: tt.opts})
+			if !e.playPath(rule, r.playerPath) || e.playPath(rule, r.enemyPath) {
+				t.Errorf("paths do not replay: player %v, enemy %v", r.playerPath, r.enemyPath)
+			}
+			if again := run(); !reflect.DeepEqual(r, again) {
+				t.Errorf("proof not deterministic: %+v vs %+v", r, again)
+			}
+		})
+	}
+}
+
+// TestProofStrategies_Comparison: DeepFilter を通過した同じ候補に対し、同じ予算で MCTS と DFS の証明フェーズを比べる
+// 予算が小さいほど差が出る（DFS は 1 本の末端に battleMax+1 ノードを使い、MCTS は反復ごとに末端まで打ち切りなく進む）
+func TestProofStrategies_Comparison(t *testing.T) {
+	if testing.Short() {
+		t.Skip("measurement")
+	}
+	twoByTwo := GameConfig{PlayerMatrix: [][]float64{{2, 0}, {0, 2}}, PlayerGrowth: 0.5, EnemyMatrix: [][]float64{{0, 2}, {2, 0}}, EnemyGrowth: 0.5}
+	tests := []struct {
+		name      string
+		config    GameConfig
+		battleMax int
+		budgets   []int
+	}{
+		{"default 3x3, 10 battles", DefaultGameConfig(), 10, []int{10, 30, 100}},
+		{"2x2, 5 battles", twoByTwo, 5, []int{5, 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 証明フェーズまで進む候補を集める
+			player, enemy := tt.config.NewCombatants()
+			filter := newSeedEvaluator(tt.battleMax, player, enemy, DefaultSeedCriteria(), SeedSearchOptions{})
+			rng := logic.NewSeedManagerWithFixedValue(1)
+			var candidates []int64
+			for i := 0; i < 120; i++ {
+				seed := rng.Int63()
+				if stage, _ := filter.evaluate(context.Background(), seed); stage >= stageProof {
+					candidates = append(candidates, seed)
+				}
+			}
+			if len(candidates) == 0 {
+				t.Fatal("no candidate reached the proof phase")
+			}
+
+			for _, budget := range tt.budgets {
+				t.Run(fmt.Sprintf("budget %d", budget), func(t *testing.T) {
+					type stats struct{ proved, leaves, nodes int }
+					got := map[ProofStrategy]stats{}
+					for _, s := range []ProofStrategy{ProofDFS, ProofMCTS} {
+						player, enemy := tt.config.NewCombatants()
+						e := newSeedEvaluator(tt.battleMax, player, enemy, DefaultSeedCriteria(), SeedSearchOptions{Proof: ProofOptions{Strategy: s, Iterations: budget}})
+						var st stats
+						for _, seed := range candidates {
+							// evaluate と同じく候補から派生した乱数で証明する
+							e.rng = logic.NewSeedManagerWithFixedValue(seed).Derive(logic.StreamSeedSearch)
+							r := e.proofPhase(NewRuleForSeed(seed, e.size))
+							if r.ok {
+								st.proved++
+							}
+							st.leaves += r.playerLeaves + r.enemyLeaves
+							st.nodes += r.nodes
+						}
+						got[s] = st
+					}
+					dfs, mcts := got[ProofDFS], got[ProofMCTS]
+					t.Logf("proved %d/%d, %d leaves, %d nodes (DFS) vs %d/%d, %d leaves, %d nodes (MCTS)",
+						dfs.proved, len(candidates), dfs.leaves, dfs.nodes, mcts.proved, len(candidates), mcts.leaves, mcts.nodes)
+					if mcts.proved < dfs.proved {
+						t.Errorf("MCTS proved %d candidates, fewer than DFS (%d)", mcts.proved, dfs.proved)
+					}
+					if mcts.leaves <= dfs.leaves {
+						t.Errorf("MCTS reached %d leaves, not more than DFS (%d)", mcts.leaves, dfs.leaves)
+					}
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkProofPhase(b *testing.B) {
+	for _, s := range []ProofStrategy{ProofDFS, ProofMCTS} {
+		b.Run(s.String(), func(b *testing.B) {
+			player, enemy := newProofPair()
+			rule := NewRuleForSeed(1, 2)
+			e := newSeedEvaluator(5, player, enemy, DefaultSeedCriteria(), SeedSearchOptions{Proof: ProofOptions{Strategy: s}})
+			e.rng = logic.NewSeedManagerWithFixedValue(1)
+			for i := 0; i < b.N; i++ {
+				e.proofPhase(rule)
+			}
+		})
+	}
+}
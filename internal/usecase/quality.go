@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/logic"
+	"math"
+)
+
+// SeedQuality describes structural weaknesses of a seed beyond its win rate.
+type SeedQuality struct {
+	Trivial        bool  `json:"trivial"`          // 毎ターン同じ入力を続けるだけで勝てる（battleMax 2 以上のみ判定）
+	TrivialInputs  []int `json:"trivial_inputs"`   // そのように勝てる入力
+	OneMoveDecided bool  `json:"one_move_decided"` // 初手だけで勝敗が決まり、以降の入力が結果に影響しない（battleMax 2 以上のみ判定）
+	ExactSequence  bool  `json:"exact_sequence"`   // 既知の勝ち入力列から 1 手でも変えると負ける
+	// 勝ちに繋がる初手の分布のエントロピー（bit、0〜log2(10)）。大きいほど初手の選択肢が多様
+	FirstMoveEntropy float64 `json:"first_move_entropy"`
+	// 最終ターンの直前まで同じ入力で進めたとき、最後の入力次第で勝敗が変わる局面の割合
+	LateSensitivity float64 `json:"late_sensitivity"`
+}
+
+// QualityThresholds decides which SeedQuality values make a seed unacceptable.
+type QualityThresholds struct {
+	AllowTrivial        bool
+	AllowOneMoveDecided bool
+	AllowExactSequence  bool
+	MinFirstMoveEntropy float64 // 0 なら判定しない
+	MinLateSensitivity  float64 // 0 なら判定しない
+}
+
+// Issues lists the reasons q fails the thresholds (empty if it passes).
+func (t QualityThresholds) Issues(q SeedQuality) []string {
+	var issues []string
+	if q.Trivial && !t.AllowTrivial {
+		issues = append(issues, "trivial")
+	}
+	if q.OneMoveDecided && !t.AllowOneMoveDecided {
+		issues = append(issues, "one-move-decided")
+	}
+	if q.ExactSequence && !t.AllowExactSequence {
+		issues = append(issues, "exact-sequence")
+	}
+	if q.FirstMoveEntropy < t.MinFirstMoveEntropy {
+		issues = append(issues, "low first-move entropy")
+	}
+	if q.LateSensitivity < t.MinLateSensitivity {
+		issues = append(issues, "late inputs do not matter")
+	}
+	return issues
+}
+
+// AnalyzeSeed inspects the rule generated from seed for degenerate structure.
+// winningPath is a known winning input sequence such as SeedReport.PlayerPath;
+// ExactSequence is only evaluated when it is given. player and enemy are not modified.
+func AnalyzeSeed(seed int64, battleMax int, player *domain.Player, enemy *domain.Enemy, winningPath []int) SeedQuality {
+	if battleMax <= 0 || player == nil || enemy == nil {
+		panic("Invalid parameters: battleMax must be > 0, player and enemy must not be nil")
+	}
+	e := newSeedEvaluator(battleMax, player.Clone(), enemy.Clone(), DefaultSeedCriteria(), SeedSearchOptions{})
+	var winning []float64
+	if winningPath != nil {
+		winning = digitInputs(winningPath)
+	}
+	q, _ := e.analyzeQuality(seed, NewRuleForSeed(seed, e.size), winning) // ctx は Background なので中断しない
+	return q
+}
+
+// analyzeQuality: seed から派生した専用の乱数ストリームで品質を測る
+// 探索中でも単体の AnalyzeSeed でも同じ結果になるよう、e.rng を差し替える（evaluate の最後でのみ呼ぶ）
+// 入力の粒度によらず 10 キーの値で調べる（より細かい粒度は 10 キーの値を全て含む）
+// ctx がキャンセルされると途中で打ち切り、そこまでの結果と ctx.Err() を返す
+func (e *seedEvaluator) analyzeQuality(seed int64, rule *domain.RuleMatrix, winningPath []float64) (SeedQuality, error) {
+	e.rng = logic.NewSeedManagerWithFixedValue(seed).Derive(logic.StreamSeedQuality)
+	q := SeedQuality{}
+
+	// 同じ入力の繰り返し
+	repeated := make([]int, e.battleMax)
+	for v := 0; v < 10 && e.battleMax > 1; v++ {
+		if e.cancelled() {
+			return q, e.ctx.Err()
+		}
+		for i := range repeated {
+			repeated[i] = v
+		}
+		if e.playPath(rule, repeated) {
+			q.TrivialInputs = append(q.TrivialInputs, v)
+		}
+	}
+	q.Trivial = len(q.TrivialInputs) > 0
+
+	// 初手ごとにランダムな続きを試し、勝ち数の分布と初手以降の影響を調べる
+	wins := make([]int, 10)
+	decided := e.battleMax > 1
+	inputs := make([]int, e.battleMax)
+	for first := 0; first < 10; first++ {
+		for s := 0; s < e.qualitySamples; s++ {
+			if e.cancelled() {
+				return q, e.ctx.Err()
+			}
+			inputs[0] = first
+			for i := 1; i < e.battleMax; i++ {
+				inputs[i] = e.rng.Intn(10)
+			}
+			if e.playPath(rule, inputs) {
+				wins[first]++
+			}
+		}
+		if wins[first] != 0 && wins[first] != e.qualitySamples {
+			decided = false
+		}
+	}
+	q.OneMoveDecided = decided
+	q.FirstMoveEntropy = entropyBits(wins)
+
+	// 1 手だけ変えた入力列が全て負けるか
+	if len(winningPath) == e.battleMax {
+		q.ExactSequence = true
+		neighbor := make([]float64, e.battleMax)
+		for t := 0; t < e.battleMax && q.ExactSequence; t++ {
+			for v := 0; v < 10; v++ {
+				if e.cancelled() {
+					return q, e.ctx.Err()
+				}
+				if InputDigits.Value(v) == winningPath[t] {
+					continue
+				}
+				copy(neighbor, winningPath)
+				neighbor[t] = InputDigits.Value(v)
+				if _, win := e.playInputs(rule, neighbor); win {
+					q.ExactSequence = false
+					break
+				}
+			}
+		}
+	}
+
+	q.LateSensitivity = e.lateSensitivity(rule)
+	return q, e.ctx.Err()
+}
+
+// lateSensitivity: ランダムな入力で最終ターンの直前まで進め、その状態の複製から最後の入力 10 通りを試す
+// 勝ちと負けの両方が現れた局面の割合を返す
+func (e *seedEvaluator) lateSensitivity(rule *domain.RuleMatrix) float64 {
+	sensitive := 0
+	for s := 0; s < e.qualitySamples && !e.cancelled(); s++ {
+		e.player.Reset()
+		e.enemy.Reset()
+		service := NewBattleService(e.player, e.enemy, rule)
+		for battle := 0; battle < e.battleMax-1; battle++ {
+			service.DoBattleTurn(float64(e.rng.Intn(10))/9, battle)
+		}
+		anyWin, anyLose := false, false
+		for v := 0; v < 10; v++ {
+			player, enemy := e.player.Clone(), e.enemy.Clone()
+			if _, win := NewBattleService(player, enemy, rule).DoBattleTurn(float64(v)/9, e.battleMax-1); win {
+				anyWin = true
+			} else {
+				anyLose = true
+			}
+		}
+		if anyWin && anyLose {
+			sensitive++
+		}
+	}
+	return float64(sensitive) / float64(e.qualitySamples)
+}
+
+// entropyBits: 度数分布のシャノンエントロピー（bit）。合計 0 なら 0
+func entropyBits(counts []int) float64 {
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	h := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / float64(total)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
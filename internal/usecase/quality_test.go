@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/logic"
+)
+
+func TestEntropyBits(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts []int
+		want   float64
+	}{
+		{"empty", []int{0, 0, 0}, 0},
+		{"single move", []int{0, 5, 0}, 0},
+		{"two equal moves", []int{3, 3, 0}, 1},
+		{"ten equal moves", []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, math.Log2(10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := entropyBits(tt.counts); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("entropyBits = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualityThresholds_Issues(t *testing.T) {
+	bad := SeedQuality{Trivial: true, OneMoveDecided: true, ExactSequence: true, FirstMoveEntropy: 0.5, LateSensitivity: 0.1}
+	good := SeedQuality{FirstMoveEntropy: 3, LateSensitivity: 0.5}
+	tests := []struct {
+		name string
+		t    QualityThresholds
+		q    SeedQuality
+		want []string
+	}{
+		{"default passes good seed", QualityThresholds{}, good, nil},
+		{"default flags", QualityThresholds{}, bad, []string{"trivial", "one-move-decided", "exact-sequence"}},
+		{"allow all flags", QualityThresholds{AllowTrivial: true, AllowOneMoveDecided: true, AllowExactSequence: true}, bad, nil},
+		{"minimums", QualityThresholds{AllowTrivial: true, AllowOneMoveDecided: true, AllowExactSequence: true, MinFirstMoveEntropy: 1, MinLateSensitivity: 0.2}, bad,
+			[]string{"low first-move entropy", "late inputs do not matter"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.t.Issues(tt.q); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSeed_MatchesReplay(t *testing.T) {
+	tests := []struct {
+		name      string
+		seed      int64
+		battleMax int
+	}{
+		{"fallback seed", FallbackSeed, 10},
+		{"short game", FallbackSeed, 3},
+		{"another seed", 12345, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := DefaultGameConfig().NewCombatants()
+			sol, err := Solve(tt.seed, min(tt.battleMax, 3), player, enemy, SolveOptions{MaxPaths: 1})
+			if err != nil {
+				t.Fatalf("Solve error: %v", err)
+			}
+			var path []int
+			if tt.battleMax <= 3 && sol.Winnable() {
+				path = sol.WinningPaths[0]
+			}
+			q := AnalyzeSeed(tt.seed, tt.battleMax, player, enemy, path)
+			e := newSeedEvaluator(tt.battleMax, player.Clone(), enemy.Clone(), DefaultSeedCriteria(), SeedSearchOptions{})
+			rule := NewRuleForSeed(tt.seed, 3)
+
+			var trivial []int
+			for v := 0; v < 10; v++ {
+				repeated := make([]int, tt.battleMax)
+				for i := range repeated {
+					repeated[i] = v
+				}
+				if e.playPath(rule, repeated) {
+					trivial = append(trivial, v)
+				}
+			}
+			if !reflect.DeepEqual(q.TrivialInputs, trivial) || q.Trivial != (len(trivial) > 0) {
+				t.Errorf("TrivialInputs = %v, want %v", q.TrivialInputs, trivial)
+			}
+
+			exact := path != nil
+			for turn := 0; turn < len(path) && exact; turn++ {
+				for v := 0; v < 10; v++ {
+					neighbor := append([]int(nil), path...)
+					neighbor[turn] = v
+					if v != path[turn] && e.playPath(rule, neighbor) {
+						exact = false
+					}
+				}
+			}
+			if q.ExactSequence != exact {
+				t.Errorf("ExactSequence = %v, want %v (path %v)", q.ExactSequence, exact, path)
+			}
+			if q.FirstMoveEntropy < 0 || q.FirstMoveEntropy > math.Log2(10) || q.LateSensitivity < 0 || q.LateSensitivity > 1 {
+				t.Errorf("measures out of range: %+v", q)
+			}
+			if again := AnalyzeSeed(tt.seed, tt.battleMax, player, enemy, path); !reflect.DeepEqual(q, again) {
+				t.Errorf("AnalyzeSeed not deterministic: %+v vs %+v", q, again)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSeed_Degenerate(t *testing.T) {
+	tests := []struct {
+		name      string
+		battleMax int
+		want      SeedQuality
+	}{
+		// 空行列では入力が何も変えないので、初手で（常に負けと）決まっている
+		{"never winnable", 4, SeedQuality{OneMoveDecided: true}},
+		// 1 戦だけなら「同じ入力の繰り返し」「初手で決着」は意味を持たない
+		{"single battle", 1, SeedQuality{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := domain.NewPlayer(domain.NewMatrix([][]float64{}), 0.5)
+			enemy := domain.NewEnemy("E", domain.NewMatrix([][]float64{}), 0.5)
+			if got := AnalyzeSeed(1, tt.battleMax, player, enemy, nil); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AnalyzeSeed = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSeed_GuardCases(t *testing.T) {
+	player, enemy := newProofPair()
+	tests := []struct {
+		name      string
+		battleMax int
+		player    *domain.Player
+		enemy     *domain.Enemy
+	}{
+		{"zero battleMax", 0, player, enemy},
+		{"nil player", 3, nil, enemy},
+		{"nil enemy", 3, player, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic but did not panic")
+				}
+			}()
+			AnalyzeSeed(1, tt.battleMax, tt.player, tt.enemy, nil)
+		})
+	}
+}
+
+func TestFindSeed_QualityThresholds(t *testing.T) {
+	tests := []struct {
+		name    string
+		quality QualityThresholds
+	}{
+		{"default rejects degenerate seeds", QualityThresholds{}},
+		{"minimum measures", QualityThresholds{MinFirstMoveEntropy: 1.5, MinLateSensitivity: 0.3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := DefaultGameConfig().NewCombatants()
+			c := DefaultSeedCriteria()
+			c.Quality = tt.quality
+			var last SeedProgress
+			r, err := FindSeed(context.Background(), 3, player, enemy, logic.NewSeedManagerWithFixedValue(4), c,
+				SeedSearchOptions{Progress: func(p SeedProgress) { last = p }})
+			if err != nil {
+				t.Fatalf("FindSeed error: %v", err)
+			}
+			if issues := tt.quality.Issues(r.Quality); len(issues) > 0 {
+				t.Errorf("accepted seed has issues %v: %+v", issues, r.Quality)
+			}
+			if !reflect.DeepEqual(r.Quality, AnalyzeSeed(r.Seed, 3, player, enemy, r.PlayerPath)) {
+				t.Error("report quality differs from AnalyzeSeed")
+			}
+			if last.QualityRejected == 0 {
+				t.Errorf("progress = %+v, expected some quality rejections for 3x3 / 3 battles", last)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/logic"
+	"context"
+)
+
+// ReplayStep is the state after one turn of a replayed input path.
+type ReplayStep struct {
+	Input  float64
+	Result float64
+	Win    bool
+	Player [][]float64 // ターン終了時のプレイヤー行列
+	Enemy  [][]float64 // ターン終了時の敵行列
+}
+
+// Replay plays inputs from the initial state of player and enemy with the rule for seed
+// and records every turn. player and enemy are not modified.
+func Replay(seed int64, player *domain.Player, enemy *domain.Enemy, inputs []float64) []ReplayStep {
+	if player == nil || enemy == nil {
+		panic("Invalid parameters: player and enemy must not be nil")
+	}
+	player, enemy = player.Clone(), enemy.Clone()
+	player.Reset()
+	enemy.Reset()
+	service := NewBattleService(player, enemy, NewRuleForSeed(seed, matrixSize(player)))
+	steps := make([]ReplayStep, len(inputs))
+	for battle, input := range inputs {
+		result, win := service.DoBattleTurn(input, battle)
+		steps[battle] = ReplayStep{
+			Input:  input,
+			Result: result,
+			Win:    win,
+			Player: domain.CopyRows(player.GetMatrix().Data),
+			Enemy:  domain.CopyRows(enemy.GetMatrix().Data),
+		}
+	}
+	return steps
+}
+
+
+// SolutionReplay compares the player's inputs with a known winning line, turn by turn.
+type SolutionReplay struct {
+	Solution   []ReplayStep
+	Actual     []ReplayStep
+	Divergence int // 入力が最初に食い違ったターン（0 始まり）。食い違いが無ければ -1
+}
+
+// CompareWithSolution replays both the winning line solution and the actual inputs of a game on seed.
+func CompareWithSolution(seed int64, player *domain.Player, enemy *domain.Enemy, solution, actual []float64) SolutionReplay {
+	r := SolutionReplay{
+		Solution:   Replay(seed, player, enemy, solution),
+		Actual:     Replay(seed, player, enemy, actual),
+		Divergence: -1,
+	}
+	for turn := 0; turn < max(len(solution), len(actual)); turn++ {
+		if turn >= len(solution) || turn >= len(actual) || solution[turn] != actual[turn] {
+			r.Divergence = turn
+			break
+		}
+	}
+	return r
+}
+
+// WinningPath searches a player-winning input path for seed with the proof phase of the seed finder,
+// for seeds whose SeedReport has none (unchecked share codes, FallbackSeed).
+// The search is deterministic for the same seed. It returns false if no winning path was found,
+// and ctx.Err() if ctx is cancelled before the search finishes.
+func WinningPath(ctx context.Context, seed int64, battleMax int, player *domain.Player, enemy *domain.Enemy, granularity InputGranularity) ([]float64, bool, error) {
+	if battleMax <= 0 || player == nil || enemy == nil {
+		panic("Invalid parameters: battleMax must be > 0, player and enemy must not be nil")
+	}
+	e := newSeedEvaluator(battleMax, player.Clone(), enemy.Clone(), DefaultSeedCriteria(), SeedSearchOptions{Granularity: granularity})
+	e.ctx = ctx
+	e.rng = logic.NewSeedManagerWithFixedValue(seed).Derive(logic.StreamSeedSearch)
+	proof := e.proofPhase(NewRuleForSeed(seed, e.size))
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	if len(proof.playerWinPaths) == 0 {
+		return nil, false, nil
+	}
+	return proof.playerWinPaths[0], true, nil
+}
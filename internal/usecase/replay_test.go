@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	config := smallBankConfig()
+	tests := []struct {
+		name   string
+		seed   int64
+		inputs []float64
+	}{
+		{"three turns", 13, []float64{0, 1, 0.5}},
+		{"one turn", 13, []float64{1}},
+		{"other seed", 7, []float64{0.5, 0.5, 0}},
+		{"no inputs", 13, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := config.NewCombatants()
+			steps := Replay(tt.seed, player, enemy, tt.inputs)
+			if len(steps) != len(tt.inputs) {
+				t.Fatalf("len = %d, want %d", len(steps), len(tt.inputs))
+			}
+			// 評価器での再生と同じ結果になり、player / enemy は変更されない
+			var trace PathTrace
+			if len(tt.inputs) > 0 {
+				e := newSeedEvaluator(len(tt.inputs), player.Clone(), enemy.Clone(), DefaultSeedCriteria(), SeedSearchOptions{})
+				trace = e.tracePath(NewRuleForSeed(tt.seed, config.Size()), tt.inputs)
+			}
+			for i, s := range steps {
+				if s.Input != tt.inputs[i] || s.Result != trace.Results[i] || s.Win != trace.Wins[i] {
+					t.Errorf("turn %d: %+v, want result %v win %v", i+1, s, trace.Results[i], trace.Wins[i])
+				}
+				if len(s.Player) != config.Size() || len(s.Enemy) != config.Size() {
+					t.Errorf("turn %d: matrices %v / %v", i+1, s.Player, s.Enemy)
+				}
+			}
+			if !reflect.DeepEqual(player.MatrixState.Data, config.PlayerMatrix) || !reflect.DeepEqual(enemy.MatrixState.Data, config.EnemyMatrix) {
+				t.Error("Replay modified player or enemy")
+			}
+			// 各ターンの行列はスナップショット（後のターンで書き換わらない）
+			for i := 1; i < len(steps); i++ {
+				if reflect.DeepEqual(steps[i-1].Player, steps[i].Player) && reflect.DeepEqual(steps[i-1].Enemy, steps[i].Enemy) {
+					t.Errorf("matrices did not change between turns %d and %d; snapshots may be shared", i, i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestReplay_Panics(t *testing.T) {
+	player, enemy := smallBankConfig().NewCombatants()
+	tests := []struct {
+		name string
+		call func()
+	}{
+		{"Replay with nil player", func() { Replay(1, nil, enemy, nil) }},
+		{"Replay with nil enemy", func() { Replay(1, player, nil, nil) }},
+		{"WinningPath with battleMax 0", func() { WinningPath(context.Background(), 1, 0, player, enemy, InputDigits) }},
+		{"WinningPath with nil player", func() { WinningPath(context.Background(), 1, 3, nil, enemy, InputDigits) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("should panic")
+				}
+			}()
+			tt.call()
+		})
+	}
+}
+
+func TestCompareWithSolution(t *testing.T) {
+	tests := []struct {
+		name     string
+		solution []float64
+		actual   []float64
+		want     int
+	}{
+		{"equal", []float64{0, 1, 0.5}, []float64{0, 1, 0.5}, -1},
+		{"differ at the first turn", []float64{0, 1, 0.5}, []float64{1, 1, 0.5}, 0},
+		{"differ mid-way", []float64{0, 1, 0.5}, []float64{0, 0.5, 0.5}, 1},
+		{"differ at the last turn", []float64{0, 1, 0.5}, []float64{0, 1, 0}, 2},
+		{"shorter actual", []float64{0, 1, 0.5}, []float64{0, 1}, 2},
+		{"longer actual", []float64{0, 1}, []float64{0, 1, 0.5}, 2},
+		{"empty actual", []float64{0, 1}, nil, 0},
+		{"both empty", nil, nil, -1},
+	}
+	player, enemy := smallBankConfig().NewCombatants()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := CompareWithSolution(13, player, enemy, tt.solution, tt.actual)
+			if r.Divergence != tt.want {
+				t.Errorf("Divergence = %d, want %d", r.Divergence, tt.want)
+			}
+			if len(r.Solution) != len(tt.solution) || len(r.Actual) != len(tt.actual) {
+				t.Fatalf("steps %d/%d, want %d/%d", len(r.Solution), len(r.Actual), len(tt.solution), len(tt.actual))
+			}
+			// 食い違う前のターンは両方の再生が一致する
+			for turn := 0; turn < len(r.Solution) && turn < len(r.Actual) && (tt.want < 0 || turn < tt.want); turn++ {
+				if !reflect.DeepEqual(r.Solution[turn], r.Actual[turn]) {
+					t.Errorf("turn %d differs before the divergence: %+v vs %+v", turn+1, r.Solution[turn], r.Actual[turn])
+				}
+			}
+		})
+	}
+}
+
+func TestWinningPath(t *testing.T) {
+	config := smallBankConfig()
+	player, enemy := config.NewCombatants()
+	// 勝てる seed と勝てない seed を厳密解で選ぶ
+	var winnable, unwinnable int64 = -1, -1
+	for seed := int64(1); winnable < 0 || unwinnable < 0; seed++ {
+		sol, err := Solve(seed, config.BattleMax, player, enemy, SolveOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sol.Winnable() && winnable < 0 {
+			winnable = seed
+		} else if !sol.Winnable() && unwinnable < 0 {
+			unwinnable = seed
+		}
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		seed        int64
+		granularity InputGranularity
+		wantOK      bool
+		wantErr     error
+	}{
+		{"digits", context.Background(), winnable, InputDigits, true, nil},
+		{"continuous", context.Background(), winnable, InputContinuous, true, nil},
+		{"unwinnable", context.Background(), unwinnable, InputDigits, false, nil},
+		{"cancelled", cancelled, winnable, InputDigits, false, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok, err := WinningPath(tt.ctx, tt.seed, config.BattleMax, player, enemy, tt.granularity)
+			if ok != tt.wantOK || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WinningPath(%d) = %v, %v, %v, want ok %v err %v", tt.seed, path, ok, err, tt.wantOK, tt.wantErr)
+			}
+			if !ok {
+				if path != nil {
+					t.Errorf("path = %v, want nil", path)
+				}
+				return
+			}
+			if len(path) != config.BattleMax {
+				t.Fatalf("path %v, want %d inputs", path, config.BattleMax)
+			}
+			if steps := Replay(tt.seed, player, enemy, path); !steps[len(steps)-1].Win {
+				t.Errorf("path %v does not win", path)
+			}
+			if again, _, _ := WinningPath(tt.ctx, tt.seed, config.BattleMax, player, enemy, tt.granularity); !reflect.DeepEqual(again, path) {
+				t.Errorf("not deterministic: %v vs %v", again, path)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/logic"
+)
+
+// NewRuleForSeed builds the rule matrix for a game seed from its "rule" stream.
+// Other subsystems derive their own streams from the same seed, so they can be
+// added without changing the rule matrix a seed produces.
+func NewRuleForSeed(seed int64, size int) *domain.RuleMatrix {
+	return domain.NewRuleMatrixFromSource(logic.NewSeedManagerWithFixedValue(seed).Derive(logic.StreamRule), size)
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/logic"
+)
+
+func TestNewRuleForSeed(t *testing.T) {
+	tests := []struct {
+		name string
+		seed int64
+		size int
+	}{
+		{"size 0", 1, 0},
+		{"size 2", 42, 2},
+		{"size 3", 123456789, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewRuleForSeed(tt.seed, tt.size)
+			want := domain.NewRuleMatrix(logic.DeriveSeed(tt.seed, logic.StreamRule), tt.size)
+			if got.Rows != tt.size || !reflect.DeepEqual(got.Data, want.Data) {
+				t.Errorf("NewRuleForSeed(%d, %d) = %v, want %v", tt.seed, tt.size, got.Data, want.Data)
+			}
+		})
+	}
+}
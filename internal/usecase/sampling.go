@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"axiom_shift/internal/domain"
+	"math"
+)
+
+// SamplingMode selects how RoughFilter / DeepFilter estimate the random-play win rate.
+type SamplingMode int
+
+const (
+	SamplingSequential SamplingMode = iota // 層化・対称サンプリング＋逐次検定で早期に打ち切る（既定）
+	SamplingFixed                          // 固定回数の独立サンプリング（比較用）
+)
+
+// String returns the display name of the mode.
+func (m SamplingMode) String() string {
+	if m == SamplingFixed {
+		return "fixed"
+	}
+	return "sequential"
+}
+
+const (
+	// confidenceAlpha: 勝率帯の判定全体での有意水準（99% 信頼）
+	confidenceAlpha = 0.01
+	// firstLook: 最初に判定する標本数（初手 10 通り × 対称ペア 1 巡分）
+	firstLook = 20
+	// ratePrecision: 受理前に求める信頼区間の半幅（難易度の判定に使える精度）
+	ratePrecision = 0.05
+)
+
+// sequentialLooks: 判定を行う標本数。firstLook から倍々に増やし、最後は maxSamples
+func sequentialLooks(maxSamples int) []int {
+	var looks []int
+	for n := firstLook; n < maxSamples; n *= 2 {
+		looks = append(looks, n)
+	}
+	return append(looks, maxSamples)
+}
+
+// bonferroniZ: looks 回判定しても全体で confidenceAlpha を保つ両側 z 値
+func bonferroniZ(looks int) float64 {
+	return math.Sqrt2 * math.Erfinv(1-confidenceAlpha/float64(looks))
+}
+
+// sequentialFilter: RoughFilter と DeepFilter を 1 本の標本列で逐次的に行う
+// 判定点ごとに Bonferroni 補正した Wilson 区間を求め、勝率帯の外と分かれば棄却、
+// 内側かつ十分狭ければ受理して打ち切る。最後まで決まらなければ点推定で判定する。
+// 標本は初手を 10 区分で巡回（層化）し、各入力列と 1-x に反転した列を対で評価する（対称変量）
+func (e *seedEvaluator) sequentialFilter(rule *domain.RuleMatrix, report *SeedReport) (seedStage, bool) {
+	looks := sequentialLooks(e.roughSamples + e.deepSamples)
+	z := bonferroniZ(len(looks))
+	inputs := make([]float64, e.battleMax)
+	mirror := make([]float64, e.battleMax)
+	wins, n := 0, 0
+	for _, look := range looks {
+		if e.cancelled() {
+			return stageRough, false
+		}
+		for n < look {
+			inputs[0] = e.input.classInput(e.rng, (n/2)%firstMoveClasses)
+			for i := 1; i < e.battleMax; i++ {
+				inputs[i] = e.input.random(e.rng)
+			}
+			for i, v := range inputs {
+				mirror[i] = e.input.mirror(v)
+			}
+			for _, path := range [][]float64{inputs, mirror} {
+				if _, win := e.playInputs(rule, path); win {
+					wins++
+				}
+			}
+			n += 2
+		}
+		report.Simulations = n
+
+		est := newWinRateEstimate(wins, n)
+		low, high := wilsonIntervalZ(wins, n, z)
+		stage := stageDeep
+		if n <= e.roughSamples {
+			report.Rough = est
+			stage = stageRough
+		} else {
+			report.Deep = est
+		}
+		if e.criteria.intervalOutside(low, high) {
+			return stage, false
+		}
+		if e.criteria.intervalInside(wins, n, low, high) && (high-low)/2 <= ratePrecision {
+			report.Deep = est
+			return stageDeep, true
+		}
+	}
+	return stageDeep, e.criteria.deepAccept(report.Deep.Rate)
+}
+
+// fixedFilter: RoughFilter・DeepFilter をそれぞれ固定回数の独立サンプリングで行う
+func (e *seedEvaluator) fixedFilter(rule *domain.RuleMatrix, report *SeedReport) (seedStage, bool) {
+	report.Rough = newWinRateEstimate(e.simulateSamples(rule, e.roughSamples))
+	report.Simulations = report.Rough.Samples
+	if e.criteria.roughReject(report.Rough) || e.cancelled() {
+		return stageRough, false
+	}
+	report.Deep = newWinRateEstimate(e.simulateSamples(rule, e.deepSamples))
+	report.Simulations += report.Deep.Samples
+	return stageDeep, e.criteria.deepAccept(report.Deep.Rate)
+}
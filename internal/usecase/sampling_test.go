@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"axiom_shift/internal/logic"
+)
+
+func TestSamplingMode_String(t *testing.T) {
+	tests := []struct {
+		m    SamplingMode
+		want string
+	}{
+		{SamplingSequential, "sequential"},
+		{SamplingFixed, "fixed"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSequentialLooks(t *testing.T) {
+	tests := []struct {
+		max  int
+		want []int
+	}{
+		{20, []int{20}},
+		{100, []int{20, 40, 80, 100}},
+		{1000, []int{20, 40, 80, 160, 320, 640, 1000}},
+	}
+	for _, tt := range tests {
+		if got := sequentialLooks(tt.max); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sequentialLooks(%d) = %v, want %v", tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestBonferroniZ(t *testing.T) {
+	tests := []struct {
+		looks int
+		want  float64
+	}{
+		{1, 2.5758}, // 補正なしの 99% 区間
+		{5, 3.0902}, // 5 回判定なら各回 0.2%
+	}
+	for _, tt := range tests {
+		if got := bonferroniZ(tt.looks); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("bonferroniZ(%d) = %v, want %v", tt.looks, got, tt.want)
+		}
+	}
+}
+
+func TestWilsonIntervalZ(t *testing.T) {
+	tests := []struct {
+		name              string
+		wins, n           int
+		z                 float64
+		wantLow, wantHigh float64
+	}{
+		{"99%", 30, 100, 2.576, 0.197455, 0.427436},
+		{"larger z widens", 30, 100, 3.5, 0.168875, 0.474777},
+		{"95%", 30, 100, 1.96, 0.218948, 0.395850},
+		{"no wins", 0, 10, 1.96, 0, 0.277540},
+		{"all wins", 10, 10, 1.96, 0.722460, 1},
+		{"half", 5, 10, 1.96, 0.236590, 0.763410},
+		{"rare wins", 1, 1000, 2.576, 0.000117, 0.008461},
+		{"no samples", 0, 0, 2.576, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high := wilsonIntervalZ(tt.wins, tt.n, tt.z)
+			if math.Abs(low-tt.wantLow) > 1e-6 || math.Abs(high-tt.wantHigh) > 1e-6 {
+				t.Errorf("wilsonIntervalZ(%d, %d, %v) = %v-%v, want %v-%v", tt.wins, tt.n, tt.z, low, high, tt.wantLow, tt.wantHigh)
+			}
+			// wilsonInterval は z = 2.576 の 99% 区間
+			if tt.z == 2.576 {
+				if wl, wh := wilsonInterval(tt.wins, tt.n); wl != low || wh != high {
+					t.Errorf("wilsonInterval = %v-%v, want %v-%v", wl, wh, low, high)
+				}
+			}
+		})
+	}
+}
+
+// TestSampling_Comparison: 固定回数と逐次サンプリングで、受理 1 件あたりのランダムプレイ回数と
+// 受理した seed の真の勝率（厳密解）が勝率帯に入っているかを比較する
+func TestSampling_Comparison(t *testing.T) {
+	if testing.Short() {
+		t.Skip("measurement")
+	}
+	const battleMax = 4
+	tests := []struct {
+		name     string
+		criteria SeedCriteria
+	}{
+		{"any", DefaultSeedCriteria()},
+		{"normal", CriteriaForDifficulty(DifficultyNormal)},
+		{"hard", CriteriaForDifficulty(DifficultyHard)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perAccepted := map[SamplingMode]float64{}
+			for _, m := range []SamplingMode{SamplingFixed, SamplingSequential} {
+				player, enemy := DefaultGameConfig().NewCombatants()
+				e := newSeedEvaluator(battleMax, player, enemy, tt.criteria, SeedSearchOptions{Sampling: m})
+				rng := logic.NewSeedManagerWithFixedValue(4)
+				sims, accepted, inBand := 0, 0, 0
+				for i := 0; i < 120; i++ {
+					stage, r := e.evaluate(context.Background(), rng.Int63())
+					sims += r.Simulations
+					if stage != stageAccepted {
+						continue
+					}
+					accepted++
+					sol, err := Solve(r.Seed, battleMax, player, enemy, SolveOptions{MaxPaths: 1})
+					if err != nil {
+						t.Fatalf("Solve error: %v", err)
+					}
+					if rate := sol.WinRate(); rate > 0 && rate < 1 && rate >= tt.criteria.MinWinRate && rate < tt.criteria.MaxWinRate {
+						inBand++
+					}
+				}
+				if accepted == 0 {
+					t.Fatalf("%s sampling accepted no seeds", m)
+				}
+				perAccepted[m] = float64(sims) / float64(accepted)
+				t.Logf("%s: %d accepted, %.0f simulations per accepted seed, %d/%d truly in band", m, accepted, perAccepted[m], inBand, accepted)
+				if float64(inBand) < 0.8*float64(accepted) {
+					t.Errorf("%s sampling: only %d/%d accepted seeds are truly in band", m, inBand, accepted)
+				}
+			}
+			if perAccepted[SamplingSequential] >= perAccepted[SamplingFixed] {
+				t.Errorf("sequential sampling used %.0f simulations per accepted seed, not fewer than fixed (%.0f)",
+					perAccepted[SamplingSequential], perAccepted[SamplingFixed])
+			}
+		})
+	}
+}
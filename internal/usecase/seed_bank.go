@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"axiom_shift/internal/logic"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// SeedBankVersion is the file format version. Files with another version are discarded on load.
+const SeedBankVersion = 1
+
+// EvaluatorVersion identifies the seed evaluation algorithm. Bump it whenever
+// evaluate changes what it accepts so that banked seeds are re-verified.
+const EvaluatorVersion = 1
+
+// SeedBankKey returns the hash identifying seeds valid for config and criteria:
+// matrix size, battleMax, initial matrices, growth parameters, input granularity, criteria and evaluator version.
+func SeedBankKey(config GameConfig, criteria SeedCriteria) string {
+	data, err := json.Marshal(struct {
+		Evaluator    int
+		Size         int
+		BattleMax    int
+		PlayerMatrix [][]float64
+		PlayerGrowth float64
+		EnemyMatrix  [][]float64
+		EnemyGrowth  float64
+		Granularity  InputGranularity `json:",omitempty"` // 10 キーでは粒度の導入前と同じキーになる
+		Criteria     SeedCriteria
+	}{EvaluatorVersion, config.Size(), config.BattleMax, config.PlayerMatrix, config.PlayerGrowth, config.EnemyMatrix, config.EnemyGrowth, config.Granularity, criteria})
+	if err != nil {
+		// 数値とスライスのみなので NaN / Inf を含む設定以外では起こらない
+		panic("Invalid config: " + err.Error())
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// SeedBank stores pre-verified seeds keyed by SeedBankKey.
+type SeedBank struct {
+	Version int                     `json:"version"`
+	Entries map[string][]SeedReport `json:"entries"`
+}
+
+// NewSeedBank returns an empty bank of the current version.
+func NewSeedBank() *SeedBank {
+	return &SeedBank{Version: SeedBankVersion, Entries: make(map[string][]SeedReport)}
+}
+
+// LoadSeedBank reads a bank from path. A missing file or a file of another version yields an empty bank.
+func LoadSeedBank(path string) (*SeedBank, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewSeedBank(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b := NewSeedBank()
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, err
+	}
+	if b.Version != SeedBankVersion {
+		return NewSeedBank(), nil
+	}
+	if b.Entries == nil {
+		b.Entries = make(map[string][]SeedReport)
+	}
+	return b, nil
+}
+
+// Save writes the bank to path, creating parent directories as needed.
+func (b *SeedBank) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Len returns the number of seeds banked for key.
+func (b *SeedBank) Len(key string) int {
+	return len(b.Entries[key])
+}
+
+// Add banks r under key. It returns false if the seed is already banked.
+func (b *SeedBank) Add(key string, r SeedReport) bool {
+	for _, banked := range b.Entries[key] {
+		if banked.Seed == r.Seed {
+			return false
+		}
+	}
+	b.Entries[key] = append(b.Entries[key], r)
+	return true
+}
+
+// Take removes and returns a random seed banked under key, so that each banked seed is played once.
+func (b *SeedBank) Take(key string, rng logic.RandomSource) (SeedReport, bool) {
+	reports := b.Entries[key]
+	if len(reports) == 0 {
+		return SeedReport{}, false
+	}
+	i := rng.Intn(len(reports))
+	r := reports[i]
+	reports[i] = reports[len(reports)-1]
+	reports = reports[:len(reports)-1]
+	if len(reports) == 0 {
+		delete(b.Entries, key)
+	} else {
+		b.Entries[key] = reports
+	}
+	return r, true
+}
+
+// FillSeedBank searches until count seeds are banked for config and criteria.
+// Candidates come from rng and are searched at config.Granularity (overriding opts.Granularity);
+// each found seed is handed to onAdd (e.g. to save progress).
+// It returns how many seeds were added.
+func FillSeedBank(ctx context.Context, bank *SeedBank, config GameConfig, criteria SeedCriteria, count int, rng logic.RandomSource, opts SeedSearchOptions, onAdd func(SeedReport) error) (int, error) {
+	key := SeedBankKey(config, criteria)
+	player, enemy := config.NewCombatants()
+	opts.Granularity = config.Granularity
+	added := 0
+	for bank.Len(key) < count {
+		r, err := FindSeed(ctx, config.BattleMax, player, enemy, rng, criteria, opts)
+		if err != nil {
+			return added, err
+		}
+		if !bank.Add(key, r) {
+			continue
+		}
+		added++
+		if onAdd != nil {
+			if err := onAdd(r); err != nil {
+				return added, err
+			}
+		}
+	}
+	return added, nil
+}
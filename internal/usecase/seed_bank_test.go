@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"axiom_shift/internal/logic"
+)
+
+// smallBankConfig: 探索が速い 2x2・3 戦の設定
+func smallBankConfig() GameConfig {
+	return GameConfig{
+		BattleMax:    3,
+		PlayerMatrix: [][]float64{{2, 0}, {0, 2}},
+		PlayerGrowth: 0.5,
+		EnemyName:    "E",
+		EnemyMatrix:  [][]float64{{0, 2}, {2, 0}},
+		EnemyGrowth:  0.5,
+	}
+}
+
+func mustParseConstraint(t *testing.T, src string) *Constraint {
+	t.Helper()
+	c, err := ParseConstraint(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestSeedBankKey(t *testing.T) {
+	base := DefaultGameConfig()
+	criteria := DefaultSeedCriteria()
+	key := SeedBankKey(base, criteria)
+	if len(key) != 32 || key != SeedBankKey(DefaultGameConfig(), DefaultSeedCriteria()) {
+		t.Fatalf("key %q should be a stable 32-digit hex string", key)
+	}
+	tests := []struct {
+		name     string
+		change   func(c *GameConfig)
+		criteria SeedCriteria
+		sameKey  bool
+	}{
+		{"enemy name is cosmetic", func(c *GameConfig) { c.EnemyName = "Rival" }, criteria, true},
+		{"battleMax", func(c *GameConfig) { c.BattleMax = 5 }, criteria, false},
+		{"player matrix", func(c *GameConfig) { c.PlayerMatrix = [][]float64{{1, 0, 0}, {0, 2, 0}, {0, 0, 2}} }, criteria, false},
+		{"enemy matrix", func(c *GameConfig) { c.EnemyMatrix = [][]float64{{0, 0, 1}, {0, 2, 0}, {2, 0, 0}} }, criteria, false},
+		{"size", func(c *GameConfig) { *c = smallBankConfig(); c.BattleMax = base.BattleMax }, criteria, false},
+		{"player growth", func(c *GameConfig) { c.PlayerGrowth = 0.6 }, criteria, false},
+		{"enemy growth", func(c *GameConfig) { c.EnemyGrowth = 0.6 }, criteria, false},
+		{"criteria", func(c *GameConfig) {}, CriteriaForDifficulty(DifficultyHard), false},
+		{"constraint", func(c *GameConfig) {}, SeedCriteria{MaxWinRate: 1, Constraint: mustParseConstraint(t, "lose[3]")}, false},
+		{"explicit digit granularity", func(c *GameConfig) { c.Granularity = InputDigits }, criteria, true},
+		{"granularity", func(c *GameConfig) { c.Granularity = InputContinuous }, criteria, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := DefaultGameConfig()
+			tt.change(&c)
+			if got := SeedBankKey(c, tt.criteria) == key; got != tt.sameKey {
+				t.Errorf("same key = %v, want %v", got, tt.sameKey)
+			}
+		})
+	}
+}
+
+func TestSeedBank_AddTake(t *testing.T) {
+	b := NewSeedBank()
+	for _, seed := range []int64{1, 2, 3} {
+		if !b.Add("k", SeedReport{Seed: seed}) {
+			t.Errorf("Add(%d) = false, want true", seed)
+		}
+	}
+	if b.Add("k", SeedReport{Seed: 2}) {
+		t.Error("duplicate Add should return false")
+	}
+	if b.Len("k") != 3 || b.Len("other") != 0 {
+		t.Fatalf("Len = %d/%d, want 3/0", b.Len("k"), b.Len("other"))
+	}
+	rng := logic.NewSeedManagerWithFixedValue(1)
+	taken := map[int64]bool{}
+	for i := 0; i < 3; i++ {
+		r, ok := b.Take("k", rng)
+		if !ok || taken[r.Seed] {
+			t.Fatalf("Take #%d = %d, %v", i, r.Seed, ok)
+		}
+		taken[r.Seed] = true
+	}
+	if _, ok := b.Take("k", rng); ok {
+		t.Error("Take from an empty entry should fail")
+	}
+	if _, exists := b.Entries["k"]; exists {
+		t.Error("empty entry should be removed")
+	}
+}
+
+func TestSeedBank_LoadSave(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	corrupt := write("corrupt.json", "{")
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+		wantLen int
+	}{
+		{"missing file", filepath.Join(dir, "missing.json"), false, 0},
+		{"corrupt file", corrupt, true, 0},
+		{"null entries", write("null.json", `{"version":1,"entries":null}`), false, 0},
+		{"stale version", write("stale.json", `{"version":0,"entries":{"k":[{"seed":1}]}}`), false, 0},
+		{"current version", write("current.json", `{"version":1,"entries":{"k":[{"seed":1}]}}`), false, 1},
+		{"directory", dir, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := LoadSeedBank(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadSeedBank error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (b.Len("k") != tt.wantLen || b.Version != SeedBankVersion || b.Entries == nil) {
+				t.Errorf("bank = %+v, want %d seeds of version %d", b, tt.wantLen, SeedBankVersion)
+			}
+		})
+	}
+
+	path := filepath.Join(dir, "nested", "bank.json")
+	b := NewSeedBank()
+	report := SeedReport{Seed: 42, PlayerPath: []int{1, 2}, Deep: newWinRateEstimate(3, 10), Quality: SeedQuality{FirstMoveEntropy: 1.5}}
+	b.Add("k", report)
+	if err := b.Save(path); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	loaded, err := LoadSeedBank(path)
+	if err != nil {
+		t.Fatalf("LoadSeedBank error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Entries["k"], []SeedReport{report}) {
+		t.Errorf("round trip = %+v", loaded.Entries["k"])
+	}
+	if err := b.Save(filepath.Join(corrupt, "x.json")); err == nil {
+		t.Error("Save under a file should fail")
+	}
+}
+
+func TestFillSeedBank(t *testing.T) {
+	config := smallBankConfig()
+	criteria := DefaultSeedCriteria()
+	key := SeedBankKey(config, criteria)
+	saveErr := errors.New("disk full")
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		preloaded int
+		onAddErr  error
+		wantAdded int
+		wantErr   error
+	}{
+		{"fills empty bank", context.Background(), 0, nil, 3, nil},
+		{"tops up", context.Background(), 2, nil, 1, nil},
+		{"already full", context.Background(), 3, nil, 0, nil},
+		{"onAdd error stops", context.Background(), 0, saveErr, 1, saveErr},
+		{"cancelled", cancelled, 0, nil, 0, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewSeedBank()
+			for i := 0; i < tt.preloaded; i++ {
+				b.Add(key, SeedReport{Seed: int64(-1 - i)})
+			}
+			var added []SeedReport
+			n, err := FillSeedBank(tt.ctx, b, config, criteria, 3, logic.NewSeedManagerWithFixedValue(9), SeedSearchOptions{},
+				func(r SeedReport) error {
+					added = append(added, r)
+					return tt.onAddErr
+				})
+			if !errors.Is(err, tt.wantErr) || n != tt.wantAdded || len(added) != tt.wantAdded {
+				t.Fatalf("FillSeedBank = %d, %v (onAdd %d), want %d, %v", n, err, len(added), tt.wantAdded, tt.wantErr)
+			}
+			player, enemy := config.NewCombatants()
+			for _, r := range added {
+				if _, ok := CheckSeed(r.Seed, config.BattleMax, player, enemy); !ok {
+					t.Errorf("banked seed %d fails CheckSeed", r.Seed)
+				}
+			}
+		})
+	}
+}
+
+func TestFillSeedBank_Granularity(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   InputGranularity
+		opts     InputGranularity // config の粒度で上書きされる
+		wantPath bool             // 10 キーの入力列も残る
+	}{
+		{"continuous", InputContinuous, InputPercent, false},
+		{"percent", InputPercent, InputContinuous, false},
+		{"digits", InputDigits, InputContinuous, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := smallBankConfig()
+			config.Granularity = tt.config
+			criteria := DefaultSeedCriteria()
+			b := NewSeedBank()
+			n, err := FillSeedBank(context.Background(), b, config, criteria, 2, logic.NewSeedManagerWithFixedValue(9), SeedSearchOptions{Granularity: tt.opts}, nil)
+			if err != nil || n != 2 {
+				t.Fatalf("FillSeedBank = %d, %v", n, err)
+			}
+			other := config
+			other.Granularity = tt.opts
+			if b.Len(SeedBankKey(config, criteria)) != 2 || b.Len(SeedBankKey(other, criteria)) != 0 {
+				t.Fatalf("seeds banked under the wrong key: %v", b.Entries)
+			}
+			for _, r := range b.Entries[SeedBankKey(config, criteria)] {
+				if (r.PlayerPath != nil) != tt.wantPath || len(r.PlayerInputs) != config.BattleMax {
+					t.Errorf("seed %d: path %v inputs %v, want path %v", r.Seed, r.PlayerPath, r.PlayerInputs, tt.wantPath)
+				}
+				for _, x := range r.PlayerInputs {
+					if tt.config.Quantize(x) != x {
+						t.Errorf("seed %d: input %v is not a %s input", r.Seed, x, tt.config)
+					}
+				}
+			}
+		})
+	}
+}
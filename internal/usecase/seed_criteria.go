@@ -0,0 +1,78 @@
+package usecase
+
+import "math"
+
+// extremeRate: これ未満（1-extremeRate 以上）の勝率は実質 0（1）とみなす
+const extremeRate = 0.01
+
+// SeedCriteria describes which seeds FindSeed accepts on top of "both sides can win".
+// The win-rate band is half-open: MinWinRate <= rate < MaxWinRate, measured by DeepFilter.
+type SeedCriteria struct {
+	MinWinRate           float64 // ランダムプレイ勝率の下限（含む）
+	MaxWinRate           float64 // ランダムプレイ勝率の上限（含まない）
+	MinWinningFirstMoves int     // 勝ちに繋がる異なる初手の最小数
+	MinDecisiveDepth     int     // 勝敗がまだ分岐しうる最も深いターン（1 始まり）の最小値。battleMax で頭打ち
+	Quality              QualityThresholds
+	// 勝ちパスの少なくとも 1 本が満たすべき制約（nil なら制約なし）。参照するターンは battleMax 以下
+	Constraint *Constraint `json:",omitempty"`
+}
+
+// DefaultSeedCriteria accepts every seed where both sides can win and that is not
+// trivial, one-move-decided or an exact sequence (FindValidSeed の基準).
+func DefaultSeedCriteria() SeedCriteria {
+	return SeedCriteria{MinWinRate: 0, MaxWinRate: 1}
+}
+
+// CriteriaForDifficulty returns the criteria whose win-rate band matches DifficultyForWinRate
+// for d, with harder levels also requiring the outcome to stay open for longer.
+func CriteriaForDifficulty(d Difficulty) SeedCriteria {
+	switch d {
+	case DifficultyEasy:
+		return SeedCriteria{MinWinRate: 0.5, MaxWinRate: 1, MinWinningFirstMoves: 3, MinDecisiveDepth: 1}
+	case DifficultyNormal:
+		return SeedCriteria{MinWinRate: 0.25, MaxWinRate: 0.5, MinWinningFirstMoves: 2, MinDecisiveDepth: 2}
+	case DifficultyHard:
+		return SeedCriteria{MinWinRate: 0.1, MaxWinRate: 0.25, MinWinningFirstMoves: 1, MinDecisiveDepth: 4}
+	case DifficultyExpert:
+		return SeedCriteria{MinWinRate: 0, MaxWinRate: 0.1, MinWinningFirstMoves: 1, MinDecisiveDepth: 6}
+	}
+	return DefaultSeedCriteria()
+}
+
+// valid: 勝率帯が [0, 1] 内で空でなく、最小数が負でないか
+func (c SeedCriteria) valid() bool {
+	return c.MinWinRate >= 0 && c.MaxWinRate <= 1 && c.MinWinRate < c.MaxWinRate &&
+		c.MinWinningFirstMoves >= 0 && c.MinDecisiveDepth >= 0
+}
+
+// roughReject: RoughFilter の信頼区間が勝率帯と重ならない（またはほぼ 0 / 1）なら true
+func (c SeedCriteria) roughReject(est WinRateEstimate) bool {
+	if !(est.Low < 1-extremeRate && est.High > extremeRate) { // ほぼ 0 でも 1 でもない
+		return true
+	}
+	return est.High < c.MinWinRate || est.Low >= c.MaxWinRate
+}
+
+// deepAccept: DeepFilter の推定勝率が 0 でも 1 でもなく、勝率帯に入っているか
+func (c SeedCriteria) deepAccept(rate float64) bool {
+	return rate > 0 && rate < 1 && rate >= c.MinWinRate && rate < c.MaxWinRate
+}
+
+// intervalOutside: 信頼区間が勝率帯（ほぼ 0 / 1 の領域を除く）と重ならなければ true
+func (c SeedCriteria) intervalOutside(low, high float64) bool {
+	return high < math.Max(c.MinWinRate, extremeRate) || low >= math.Min(c.MaxWinRate, 1-extremeRate)
+}
+
+// intervalInside: 勝ちと負けの両方を観測し、信頼区間が勝率帯に収まっていれば true
+func (c SeedCriteria) intervalInside(wins, n int, low, high float64) bool {
+	return wins > 0 && wins < n && low >= c.MinWinRate && high < c.MaxWinRate
+}
+
+// proofAccept: ProofPhase で分かった初手の多様性・決着の深さが基準を満たすか
+func (c SeedCriteria) proofAccept(winningFirstMoves, decisiveDepth, battleMax int) bool {
+	minDepth := c.MinDecisiveDepth
+	if minDepth > battleMax {
+		minDepth = battleMax
+	}
+	return winningFirstMoves >= c.MinWinningFirstMoves && decisiveDepth >= minDepth
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import "testing"
+
+func TestCriteriaForDifficulty(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Difficulty
+		rate float64 // 勝率帯の内側の値
+	}{
+		{"easy", DifficultyEasy, 0.7},
+		{"normal", DifficultyNormal, 0.3},
+		{"hard", DifficultyHard, 0.15},
+		{"expert", DifficultyExpert, 0.05},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := CriteriaForDifficulty(tt.d)
+			if !c.valid() {
+				t.Fatalf("criteria %+v should be valid", c)
+			}
+			if !c.deepAccept(tt.rate) || DifficultyForWinRate(tt.rate) != tt.d {
+				t.Errorf("rate %v should be accepted and rated %v", tt.rate, tt.d)
+			}
+			// 勝率帯の境界は DifficultyForWinRate と一致する
+			if got := DifficultyForWinRate(c.MinWinRate); got != tt.d {
+				t.Errorf("DifficultyForWinRate(MinWinRate %v) = %v, want %v", c.MinWinRate, got, tt.d)
+			}
+			if c.MaxWinRate < 1 && DifficultyForWinRate(c.MaxWinRate) == tt.d {
+				t.Errorf("MaxWinRate %v should belong to an easier difficulty", c.MaxWinRate)
+			}
+		})
+	}
+	if got := CriteriaForDifficulty(DifficultyAny); got != DefaultSeedCriteria() {
+		t.Errorf("CriteriaForDifficulty(Any) = %+v, want default", got)
+	}
+}
+
+func TestSeedCriteria_Valid(t *testing.T) {
+	tests := []struct {
+		name string
+		c    SeedCriteria
+		want bool
+	}{
+		{"default", DefaultSeedCriteria(), true},
+		{"band", SeedCriteria{MinWinRate: 0.1, MaxWinRate: 0.3, MinWinningFirstMoves: 2, MinDecisiveDepth: 3}, true},
+		{"empty band", SeedCriteria{MinWinRate: 0.3, MaxWinRate: 0.3}, false},
+		{"inverted band", SeedCriteria{MinWinRate: 0.5, MaxWinRate: 0.1}, false},
+		{"negative min", SeedCriteria{MinWinRate: -0.1, MaxWinRate: 1}, false},
+		{"max above 1", SeedCriteria{MinWinRate: 0, MaxWinRate: 1.5}, false},
+		{"negative first moves", SeedCriteria{MaxWinRate: 1, MinWinningFirstMoves: -1}, false},
+		{"negative depth", SeedCriteria{MaxWinRate: 1, MinDecisiveDepth: -1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.valid(); got != tt.want {
+				t.Errorf("valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeedCriteria_Filters(t *testing.T) {
+	band := SeedCriteria{MinWinRate: 0.1, MaxWinRate: 0.3, MinWinningFirstMoves: 2, MinDecisiveDepth: 4}
+	t.Run("roughReject", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			c        SeedCriteria
+			low, hi  float64
+			wantDrop bool
+		}{
+			{"default keeps middle", DefaultSeedCriteria(), 0.2, 0.6, false},
+			{"default drops almost 0", DefaultSeedCriteria(), 0, 0.005, true},
+			{"default drops almost 1", DefaultSeedCriteria(), 0.995, 1, true},
+			{"band overlaps", band, 0.25, 0.4, false},
+			{"band below", band, 0.01, 0.05, true},
+			{"band above", band, 0.3, 0.5, true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if got := tt.c.roughReject(WinRateEstimate{Low: tt.low, High: tt.hi}); got != tt.wantDrop {
+					t.Errorf("roughReject(%v-%v) = %v, want %v", tt.low, tt.hi, got, tt.wantDrop)
+				}
+			})
+		}
+	})
+	t.Run("deepAccept", func(t *testing.T) {
+		tests := []struct {
+			name string
+			c    SeedCriteria
+			rate float64
+			want bool
+		}{
+			{"default middle", DefaultSeedCriteria(), 0.5, true},
+			{"default zero", DefaultSeedCriteria(), 0, false},
+			{"default one", DefaultSeedCriteria(), 1, false},
+			{"band lower bound inclusive", band, 0.1, true},
+			{"band upper bound exclusive", band, 0.3, false},
+			{"band below", band, 0.05, false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if got := tt.c.deepAccept(tt.rate); got != tt.want {
+					t.Errorf("deepAccept(%v) = %v, want %v", tt.rate, got, tt.want)
+				}
+			})
+		}
+	})
+	t.Run("proofAccept", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			firstMoves int
+			depth      int
+			battleMax  int
+			want       bool
+		}{
+			{"meets both", 2, 4, 10, true},
+			{"too few first moves", 1, 10, 10, false},
+			{"too shallow", 5, 3, 10, false},
+			{"depth capped by battleMax", 2, 3, 3, true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if got := band.proofAccept(tt.firstMoves, tt.depth, tt.battleMax); got != tt.want {
+					t.Errorf("proofAccept(%d, %d, %d) = %v, want %v", tt.firstMoves, tt.depth, tt.battleMax, got, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestDecisiveDepth(t *testing.T) {
+	tests := []struct {
+		name   string
+		player [][]int
+		enemy  [][]int
+		want   int
+	}{
+		{"no player path", nil, [][]int{{1, 2}}, 0},
+		{"no enemy path", [][]int{{1, 2}}, nil, 0},
+		{"differs at first move", [][]int{{1, 2, 3}}, [][]int{{2, 2, 3}}, 1},
+		{"differs at last move", [][]int{{1, 2, 3}}, [][]int{{1, 2, 4}}, 3},
+		{"deepest pair wins", [][]int{{1, 2, 3}, {4, 5, 6}}, [][]int{{1, 9, 9}, {4, 5, 0}}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decisiveDepth(tt.player, tt.enemy); got != tt.want {
+				t.Errorf("decisiveDepth = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeedCriteria_Intervals(t *testing.T) {
+	band := SeedCriteria{MinWinRate: 0.1, MaxWinRate: 0.3}
+	tests := []struct {
+		name        string
+		c           SeedCriteria
+		wins, n     int
+		low, high   float64
+		wantOutside bool
+		wantInside  bool
+	}{
+		{"default undecided", DefaultSeedCriteria(), 0, 20, 0, 0.3, false, false},
+		{"default almost zero", DefaultSeedCriteria(), 0, 2000, 0, 0.005, true, false},
+		{"default almost one", DefaultSeedCriteria(), 2000, 2000, 0.995, 1, true, false},
+		{"default both outcomes", DefaultSeedCriteria(), 5, 20, 0.05, 0.6, false, true},
+		{"band inside", band, 40, 200, 0.12, 0.28, false, true},
+		{"band overlapping", band, 50, 200, 0.18, 0.33, false, false},
+		{"band below", band, 2, 200, 0.001, 0.05, true, false},
+		{"band above", band, 100, 200, 0.4, 0.6, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.intervalOutside(tt.low, tt.high); got != tt.wantOutside {
+				t.Errorf("intervalOutside = %v, want %v", got, tt.wantOutside)
+			}
+			if got := tt.c.intervalInside(tt.wins, tt.n, tt.low, tt.high); got != tt.wantInside {
+				t.Errorf("intervalInside = %v, want %v", got, tt.wantInside)
+			}
+		})
+	}
+}
@@ -3,182 +3,477 @@ package usecase
 import (
 	"axiom_shift/internal/domain"
 	"axiom_shift/internal/logic"
+	"context"
 	"fmt"
 	"math"
-	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
 )
 
-// FindValidSeed: battleMax 回のバトルで双方に勝ちパターンが存在する seed / rule / playerPath / enemyPath を返す
-func FindValidSeed(battleMax int, player *domain.Player, enemy *domain.Enemy) (int64, []int, []int, error) {
+// SeedProgress is a snapshot of a running seed search.
+type SeedProgress struct {
+	Tried              int     // 評価を終えた候補数
+	RoughRejected      int     // RoughFilter で棄却した候補数
+	DeepRejected       int     // DeepFilter で棄却した候補数
+	ProofRejected      int     // ProofPhase で棄却した候補数
+	ConstraintRejected int     // 制約を満たす勝ちパスが見つからず棄却した候補数
+	QualityRejected    int     // 品質分析で棄却した候補数
+	Simulations        int     // RoughFilter / DeepFilter で行ったランダムプレイの総数
+	HasBest            bool    // DeepFilter まで進んだ候補があるか
+	BestSeed           int64   // 推定勝率が 0.5 に最も近い候補
+	BestWinRate        float64 // BestSeed の推定勝率
+}
+
+// SeedSearchOptions tunes FindValidSeed. The zero value uses the defaults.
+type SeedSearchOptions struct {
+	Workers  int                // 並列ワーカー数（0 以下なら GOMAXPROCS）
+	MaxTries int                // 評価する候補数の上限（0 以下なら 1000）
+	Progress func(SeedProgress) // 候補を 1 つ評価するたびに呼ばれる（呼び出しは直列化される）
+	Proof    ProofOptions       // ProofPhase の探索方法
+	Sampling SamplingMode       // RoughFilter / DeepFilter の標本の取り方
+	// 入力の粒度。InputDigits 以外では ProofPhase を木探索ではなく座標探索で行う
+	Granularity InputGranularity
+}
+
+const defaultMaxTries = 1000
+
+// FindValidSeed: battleMax 回のバトルで双方に勝ちパターンが存在する seed を探し、その SeedReport を返す
+// FindSeed を DefaultSeedCriteria で呼ぶのと同じ
+func FindValidSeed(ctx context.Context, battleMax int, player *domain.Player, enemy *domain.Enemy, rng logic.RandomSource, opts SeedSearchOptions) (SeedReport, error) {
+	return FindSeed(ctx, battleMax, player, enemy, rng, DefaultSeedCriteria(), opts)
+}
+
+// FindSeed: 双方に勝ちパターンが存在し、さらに criteria を満たす seed を探し、その SeedReport を返す
+// 候補 seed は rng から順に生成し、各候補の評価は候補自身から派生した乱数ストリームで行う。
+// 複数ワーカーで並列評価しても候補順で最初に妥当だった seed を返すため、rng のシードが同じなら結果も同じになる。
+// player / enemy はワーカーごとに複製し変更しない。ctx がキャンセルされると ctx.Err() を返す
+func FindSeed(ctx context.Context, battleMax int, player *domain.Player, enemy *domain.Enemy, rng logic.RandomSource, criteria SeedCriteria, opts SeedSearchOptions) (SeedReport, error) {
+	if battleMax <= 0 || player == nil || enemy == nil || rng == nil {
+		panic("Invalid parameters: battleMax must be > 0, player, enemy and rng must not be nil")
+	}
+	if !criteria.valid() {
+		panic("Invalid criteria: win-rate band must be a non-empty range within [0, 1] and minimums must be >= 0")
+	}
+	if criteria.Constraint != nil && criteria.Constraint.MaxTurn() > battleMax {
+		panic("Invalid criteria: constraint refers to a turn after battleMax")
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	maxTries := opts.MaxTries
+	if maxTries < 1 {
+		maxTries = defaultMaxTries
+	}
+	candidates := make([]int64, maxTries)
+	for i := range candidates {
+		candidates[i] = rng.Int63()
+	}
+
+	var (
+		reports  = make([]SeedReport, maxTries)
+		next     atomic.Int64 // 次に評価する候補 index
+		found    atomic.Int64 // 妥当だった最小の候補 index
+		mu       sync.Mutex   // progress の更新と通知を直列化
+		progress SeedProgress
+		wg       sync.WaitGroup
+	)
+	found.Store(int64(maxTries))
+
+	notify := func(stage seedStage, r SeedReport) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress.Tried++
+		progress.Simulations += r.Simulations
+		switch stage {
+		case stageRough:
+			progress.RoughRejected++
+		case stageDeep:
+			progress.DeepRejected++
+		case stageProof:
+			progress.ProofRejected++
+		case stageConstraint:
+			progress.ConstraintRejected++
+		case stageQuality:
+			progress.QualityRejected++
+		}
+		if stage > stageRough && (!progress.HasBest || math.Abs(r.Deep.Rate-0.5) < math.Abs(progress.BestWinRate-0.5)) {
+			progress.HasBest = true
+			progress.BestSeed = r.Seed
+			progress.BestWinRate = r.Deep.Rate
+		}
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ev := newSeedEvaluator(battleMax, player.Clone(), enemy.Clone(), criteria, opts)
+			for ctx.Err() == nil {
+				i := next.Add(1) - 1
+				// 既に見つかった候補より後ろは評価不要（前の候補は必ず評価し終えてから返す）
+				if i >= int64(maxTries) || i > found.Load() {
+					return
+				}
+				stage, r := ev.evaluate(ctx, candidates[i])
+				// 途中で打ち切った評価は結果に含めない
+				if ctx.Err() != nil {
+					return
+				}
+				r.Tries = int(i) + 1
+				reports[i] = r
+				if stage == stageAccepted {
+					for cur := found.Load(); i < cur && !found.CompareAndSwap(cur, i); cur = found.Load() {
+					}
+				}
+				notify(stage, r)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return SeedReport{}, err
+	}
+	if i := found.Load(); i < int64(maxTries) {
+		return reports[i], nil
+	}
+	return SeedReport{}, fmt.Errorf("valid seed not found after %d tries", maxTries)
+}
+
+// CheckSeed: 指定 seed が FindValidSeed と同じ基準を満たすか検証し、その SeedReport を返す
+// 入力は 10 キーとして検証する。より細かい粒度は 10 キーの入力を全て含むため、そのまま有効になる
+// 評価は seed から派生した乱数ストリームで行うため、探索時と同じ判定になる。棄却時もそこまでの推定値は埋まる
+func CheckSeed(seed int64, battleMax int, player *domain.Player, enemy *domain.Enemy) (SeedReport, bool) {
 	if battleMax <= 0 || player == nil || enemy == nil {
 		panic("Invalid parameters: battleMax must be > 0, player and enemy must not be nil")
 	}
+	stage, r := newSeedEvaluator(battleMax, player, enemy, DefaultSeedCriteria(), SeedSearchOptions{}).evaluate(context.Background(), seed)
+	r.Tries = 1
+	return r, stage == stageAccepted
+}
+
+// seedStage: 候補 seed がどのフィルタまで進んだか
+type seedStage int
+
+const (
+	stageRough      seedStage = iota // RoughFilter で棄却
+	stageDeep                        // DeepFilter で棄却
+	stageProof                       // ProofPhase で棄却
+	stageConstraint                  // 制約を満たす勝ちパスが見つからず棄却
+	stageQuality                     // 品質分析で棄却
+	stageAccepted                    // 全フィルタ通過
+)
+
+// seedEvaluator: 1 つの候補 seed を RoughFilter → DeepFilter → ProofPhase の順に評価する
+type seedEvaluator struct {
+	battleMax int
+	player    *domain.Player
+	enemy     *domain.Enemy
+	criteria  SeedCriteria
+	proof     ProofOptions
+	sampling  SamplingMode
+	input     InputGranularity
+	rng       logic.RandomSource // 評価中の候補から派生した乱数
+	ctx       context.Context    // 評価中の探索の ctx（キャンセルされるとサンプリング・証明を打ち切る）
+	size      int
+	// サンプリング数・ノード数をサイズ依存で調整（逐次サンプリングでは合計が上限）
+	roughSamples   int
+	deepSamples    int
+	qualitySamples int // 品質分析で初手ごと・最終手直前の局面として試す数
+	dfsWidth       int
+	proofBudget    int // MCTS の反復数・DFS のノード数・座標探索の評価回数の上限
+	exploration    float64
+	rollout        RolloutPolicy
+}
+
+func newSeedEvaluator(battleMax int, player *domain.Player, enemy *domain.Enemy, criteria SeedCriteria, opts SeedSearchOptions) *seedEvaluator {
 	// 行列サイズに応じてパラメータ自動調整
-	size := 2
-	if player.MatrixState != nil && player.MatrixState.Rows > 0 {
-		size = player.MatrixState.Rows
-	}
-	// サンプリング数・ノード数をサイズ依存で調整
-	roughSamples := 50 * size * size
-	deepSamples := 200 * size * size
-	mctsWidth := 3
-	mctsMaxNodes := 1000 * size * size
-	maxTries := 1000
-
-	// Wilson score interval (近似) で勝率信頼区間を求める
-	betaCI := func(wins, n int) (float64, float64) {
-		if n == 0 {
-			return 0, 1
-		}
-		p := float64(wins) / float64(n)
-		z := 2.576 // 99% 信頼区間
-		denom := 1 + z*z/float64(n)
-		center := p + z*z/(2*float64(n))
-		pm := z * math.Sqrt(p*(1-p)/float64(n)+z*z/(4*float64(n)*float64(n)))
-		low := (center - pm) / denom
-		high := (center + pm) / denom
-		if low < 0 {
-			low = 0
-		}
-		if high > 1 {
-			high = 1
-		}
-		return low, high
-	}
-
-	// サンプリングによる勝率推定
-	simulateSamples := func(rule *domain.RuleMatrix, samples int) (int, int) {
-		playerWins := 0
-		for s := 0; s < samples; s++ {
-			player.Reset()
-			enemy.Reset()
-			service := NewBattleService(player, enemy, rule)
-
-			inputs := make([]int, battleMax)
-			for i := range inputs {
-				inputs[i] = rand.Intn(10)
-			}
+	size := matrixSize(player)
+	proof := opts.Proof
+	e := &seedEvaluator{
+		ctx:            context.Background(),
+		battleMax:      battleMax,
+		player:         player,
+		enemy:          enemy,
+		criteria:       criteria,
+		proof:          proof,
+		sampling:       opts.Sampling,
+		input:          opts.Granularity,
+		size:           size,
+		roughSamples:   50 * size * size,
+		deepSamples:    200 * size * size,
+		qualitySamples: 10 * size * size,
+		dfsWidth:       3,
+		proofBudget:    200 * size * size, // MCTS は DFS より少ない反復で証明できる
+		exploration:    DefaultExploration,
+		rollout:        UniformRollout,
+	}
+	if proof.Strategy == ProofDFS && opts.Granularity == InputDigits {
+		e.proofBudget = 1000 * size * size
+	}
+	if proof.Iterations > 0 {
+		e.proofBudget = proof.Iterations
+	}
+	if proof.Exploration > 0 {
+		e.exploration = proof.Exploration
+	}
+	if proof.Rollout != nil {
+		e.rollout = proof.Rollout
+	}
+	return e
+}
 
-			var win bool
-			for battle := 0; battle < battleMax; battle++ {
-				_, win = service.DoBattleTurn(float64(inputs[battle])/9, battle)
-			}
-			if win {
-				playerWins++
-			}
-		}
-		return playerWins, samples
+// matrixSize: ルール行列のサイズ（プレイヤー行列が空なら 2）
+func matrixSize(player *domain.Player) int {
+	if player.MatrixState != nil && player.MatrixState.Rows > 0 {
+		return player.MatrixState.Rows
 	}
+	return 2
+}
 
-	// ProofPhase: DFS＋ビーム幅＋分岐シャッフルで多様な勝ちパスを探索
-	proofPhase := func(rule *domain.RuleMatrix) (bool, []int, []int) {
-		type node struct {
-			depth  int
-			inputs []int
-		}
+// evaluate: seed を評価し、到達したステージとそこまでに分かったことを返す
+// ctx がキャンセルされると各ループを抜けて棄却扱いで返す（呼び出し側で ctx.Err() を確かめて捨てる）
+func (e *seedEvaluator) evaluate(ctx context.Context, seed int64) (seedStage, SeedReport) {
+	e.ctx = ctx
+	e.rng = logic.NewSeedManagerWithFixedValue(seed).Derive(logic.StreamSeedSearch)
+	rule := NewRuleForSeed(seed, e.size)
+	report := SeedReport{Seed: seed}
 
-		var (
-			playerPaths [][]int
-			enemyPaths  [][]int
-			nodes       int
-		)
+	// RoughFilter / DeepFilter
+	filter := e.sequentialFilter
+	if e.sampling == SamplingFixed {
+		filter = e.fixedFilter
+	}
+	if stage, ok := filter(rule, &report); !ok || e.cancelled() {
+		return stage, report
+	}
 
-		var dfs func(n node)
-		dfs = func(n node) {
-			if nodes >= mctsMaxNodes {
-				return
-			}
-			nodes++
+	// ProofPhase
+	proof := e.proofPhase(rule)
+	report.PlayerWinLeaves = proof.playerLeaves
+	report.EnemyWinLeaves = proof.enemyLeaves
+	report.NodesExplored = proof.nodes
+	report.DecisiveDepth = proof.decisiveDepth
+	if !proof.ok || e.cancelled() {
+		return stageProof, report
+	}
+	report.PlayerPath = proof.playerPath
+	report.EnemyPath = proof.enemyPath
+	report.PlayerInputs = proof.playerInputs
+	report.EnemyInputs = proof.enemyInputs
+	moves, err := e.winningFirstMoves(rule, proof.playerFirstMoves)
+	if err != nil {
+		return stageProof, report
+	}
+	report.WinningFirstMoves = moves
+	if !e.criteria.proofAccept(report.WinningFirstMoves, report.DecisiveDepth, e.battleMax) {
+		return stageProof, report
+	}
 
-			// 末端まで到達したら勝敗を判定
-			if n.depth == battleMax {
-				player.Reset()
-				enemy.Reset()
-				service := NewBattleService(player, enemy, rule)
+	// 制約: 満たす勝ちパスを代表パスにする
+	if e.criteria.Constraint != nil {
+		inputs, ok, err := e.constrainedPath(rule, proof.playerWinPaths)
+		if err != nil || !ok {
+			return stageConstraint, report
+		}
+		report.PlayerInputs = inputs
+		if e.input == InputDigits {
+			report.PlayerPath = digitPath(inputs)
+		}
+	}
 
-				var win bool
-				for battle := 0; battle < battleMax; battle++ {
-					_, win = service.DoBattleTurn(float64(n.inputs[battle])/9, battle)
-				}
-				if win {
-					// プレイヤー勝利パス
-					playerPaths = append(playerPaths, append([]int(nil), n.inputs...))
-				} else {
-					// 敵勝利パス
-					enemyPaths = append(enemyPaths, append([]int(nil), n.inputs...))
-				}
-				return
-			}
+	// 品質分析
+	quality, err := e.analyzeQuality(seed, rule, report.PlayerInputs)
+	report.Quality = quality
+	if err != nil {
+		return stageQuality, report
+	}
+	if len(e.criteria.Quality.Issues(report.Quality)) > 0 {
+		return stageQuality, report
+	}
+	return stageAccepted, report
+}
 
-			// 0.0〜1.0 を 0.1 刻み 11 通り用意し、毎ノードでシャッフル
-			choices := make([]int, 10)
-			for i := 0; i < 10; i++ {
-				choices[i] = i
-			}
-			rand.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+// wilsonInterval: Wilson score interval (近似) で勝率の 99% 信頼区間を求める
+func wilsonInterval(wins, n int) (float64, float64) {
+	return wilsonIntervalZ(wins, n, 2.576)
+}
 
-			// ランダムに mctsWidth 本を採用（幅制限）
-			if len(choices) > mctsWidth {
-				choices = choices[:mctsWidth]
-			}
+// wilsonIntervalZ: 両側 z 値を指定して Wilson score interval を求める
+func wilsonIntervalZ(wins, n int, z float64) (float64, float64) {
+	if n == 0 {
+		return 0, 1
+	}
+	p := float64(wins) / float64(n)
+	denom := 1 + z*z/float64(n)
+	center := p + z*z/(2*float64(n))
+	pm := z * math.Sqrt(p*(1-p)/float64(n)+z*z/(4*float64(n)*float64(n)))
+	low := (center - pm) / denom
+	high := (center + pm) / denom
+	if low < 0 {
+		low = 0
+	}
+	if high > 1 {
+		high = 1
+	}
+	return low, high
+}
 
-			for _, v := range choices {
-				dfs(node{depth: n.depth + 1, inputs: append(append([]int(nil), n.inputs...), v)})
-				if nodes >= mctsMaxNodes {
-					return
-				}
-			}
+// simulateSamples: サンプリングによる勝率推定。勝ち数と実際に試した数（キャンセル時は samples 未満）を返す
+func (e *seedEvaluator) simulateSamples(rule *domain.RuleMatrix, samples int) (int, int) {
+	playerWins, n := 0, 0
+	for ; n < samples && !e.cancelled(); n++ {
+		inputs := make([]float64, e.battleMax)
+		for i := range inputs {
+			inputs[i] = e.input.random(e.rng)
+		}
+		if _, win := e.playInputs(rule, inputs); win {
+			playerWins++
 		}
+	}
+	return playerWins, n
+}
 
-		dfs(node{depth: 0, inputs: []int{}})
+// cancelled: 評価中の探索がキャンセルされたか
+func (e *seedEvaluator) cancelled() bool {
+	return e.ctx.Err() != nil
+}
 
-		// 双方に少なくとも 1 パスずつあれば OK
-		if len(playerPaths) == 0 || len(enemyPaths) == 0 {
-			return false, nil, nil
-		}
+// playPath: 初期状態から 10 キーの入力列 inputs（各 0〜9）を順に入力し、最終戦の勝敗を返す
+func (e *seedEvaluator) playPath(rule *domain.RuleMatrix, inputs []int) bool {
+	_, win := e.playInputs(rule, digitInputs(inputs))
+	return win
+}
+
+// playInputs: 初期状態から inputs（各 [0, 1]）を順に入力し、最終戦の結果と勝敗を返す
+func (e *seedEvaluator) playInputs(rule *domain.RuleMatrix, inputs []float64) (float64, bool) {
+	e.player.Reset()
+	e.enemy.Reset()
+	service := NewBattleService(e.player, e.enemy, rule)
 
-		// ランダムに 1 本ずつ返す
-		playerPath := playerPaths[rand.Intn(len(playerPaths))]
-		enemyPath := enemyPaths[rand.Intn(len(enemyPaths))]
-		return true, playerPath, enemyPath
+	var (
+		result float64
+		win    bool
+	)
+	for battle := 0; battle < e.battleMax; battle++ {
+		result, win = service.DoBattleTurn(inputs[battle], battle)
 	}
+	return result, win
+}
 
-	// ——— メインループ ————————————————————————————
-	var debugSearchSeedCount int
-	for try := 0; try < maxTries; try++ {
-		seedCandidate := logic.NewSeedManager().GetSeed()
-		rule := domain.NewRuleMatrix(seedCandidate, size)
+// tracePath: 初期状態から inputs を順に入力し、各ターンの結果と勝敗を記録する
+func (e *seedEvaluator) tracePath(rule *domain.RuleMatrix, inputs []float64) PathTrace {
+	e.player.Reset()
+	e.enemy.Reset()
+	service := NewBattleService(e.player, e.enemy, rule)
+	trace := PathTrace{Inputs: inputs, Results: make([]float64, e.battleMax), Wins: make([]bool, e.battleMax)}
+	for battle := 0; battle < e.battleMax; battle++ {
+		trace.Results[battle], trace.Wins[battle] = service.DoBattleTurn(inputs[battle], battle)
+	}
+	return trace
+}
 
-		// RoughFilter
-		playerWins, n := simulateSamples(rule, roughSamples)
-		low, high := betaCI(playerWins, n)
-		if !(low < 0.99 && high > 0.01) { // ほぼ 0 でも 1 でもない
-			debugSearchSeedCount++
-			continue
+// constrainedPath: 制約を満たすプレイヤーの勝ちパスを探す
+// ProofPhase で見つかった勝ちパスを順に調べ、無ければランダムな入力列を roughSamples 本試す
+func (e *seedEvaluator) constrainedPath(rule *domain.RuleMatrix, known [][]float64) ([]float64, bool, error) {
+	satisfied := func(inputs []float64) bool {
+		trace := e.tracePath(rule, inputs)
+		return trace.Wins[e.battleMax-1] && e.criteria.Constraint.Eval(trace)
+	}
+	for _, inputs := range known {
+		if e.cancelled() {
+			return nil, false, e.ctx.Err()
 		}
-
-		// DeepFilter
-		playerWins, n = simulateSamples(rule, deepSamples)
-		pHat := float64(playerWins) / float64(n)
-		if pHat == 0 || pHat == 1 {
-			debugSearchSeedCount++
-			continue
+		if satisfied(inputs) {
+			return inputs, true, nil
+		}
+	}
+	for s := 0; s < e.roughSamples; s++ {
+		if e.cancelled() {
+			return nil, false, e.ctx.Err()
+		}
+		inputs := make([]float64, e.battleMax)
+		for i := range inputs {
+			inputs[i] = e.input.random(e.rng)
 		}
+		if satisfied(inputs) {
+			return inputs, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// digitInputs: 10 キーの入力列を [0, 1] の入力値に変換する
+func digitInputs(path []int) []float64 {
+	inputs := make([]float64, len(path))
+	for i, k := range path {
+		inputs[i] = InputDigits.Value(k)
+	}
+	return inputs
+}
 
-		// ProofPhase
-		ok, playerPath, enemyPath := proofPhase(rule)
-		debugSearchSeedCount++
-		fmt.Printf("[Proof] Seed %d (試行 %d): ok=%v\n", seedCandidate, debugSearchSeedCount, ok)
+// digitPath: 10 キーの入力値をキーの列に戻す
+func digitPath(inputs []float64) []int {
+	path := make([]int, len(inputs))
+	for i, x := range inputs {
+		path[i] = int(math.Round(x * 9))
+	}
+	return path
+}
 
-		if ok {
-			fmt.Printf("Found valid seed: %d with playerPath=%v, enemyPath=%v\n", seedCandidate, playerPath, enemyPath)
-			return seedCandidate, playerPath, enemyPath, nil
+// decisiveDepth: 勝ちパスと負けパスの最長共通接頭辞長 + 1（どちらかが空なら 0）
+// その手番の入力次第でまだ勝敗が変わりうる、最も深いターンを表す
+func decisiveDepth[T int | float64](playerPaths, enemyPaths [][]T) int {
+	if len(playerPaths) == 0 || len(enemyPaths) == 0 {
+		return 0
+	}
+	prefixes := map[string]bool{}
+	for _, p := range playerPaths {
+		for l := 0; l <= len(p); l++ {
+			prefixes[fmt.Sprint(p[:l])] = true
+		}
+	}
+	best := 0
+	for _, p := range enemyPaths {
+		for l := len(p); l > best; l-- {
+			if prefixes[fmt.Sprint(p[:l])] {
+				best = l
+				break
+			}
 		}
+	}
+	return best + 1
+}
 
-		// 進行が遅いときのデバッグ用出力（任意）
-		if debugSearchSeedCount%100 == 0 {
-			fmt.Printf("Tried %d seeds so far, still searching...\n", debugSearchSeedCount)
+// winningFirstMoves: 勝ちに繋がる異なる初手の区分の数を数える（10 キーではキーごと、それ以外は 0.1 刻み）
+// ProofPhase で見つかった区分に加え、残りの区分はランダムな初手と続きを試し、1 本でも勝てば数える
+func (e *seedEvaluator) winningFirstMoves(rule *domain.RuleMatrix, known map[int]bool) (int, error) {
+	rollouts := e.roughSamples / 10
+	count := 0
+	inputs := make([]float64, e.battleMax)
+	for first := 0; first < firstMoveClasses; first++ {
+		if known[first] {
+			count++
+			continue
+		}
+		for r := 0; r < rollouts; r++ {
+			if e.cancelled() {
+				return 0, e.ctx.Err()
+			}
+			inputs[0] = e.input.classInput(e.rng, first)
+			for i := 1; i < e.battleMax; i++ {
+				inputs[i] = e.input.random(e.rng)
+			}
+			if _, win := e.playInputs(rule, inputs); win {
+				count++
+				break
+			}
 		}
 	}
-	return 0, nil, nil, fmt.Errorf("valid seed not found after %d tries", maxTries)
+	return count, nil
 }
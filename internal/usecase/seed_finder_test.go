@@ -1,9 +1,13 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
 
 	"axiom_shift/internal/domain"
+	"axiom_shift/internal/logic"
 )
 
 func TestFindValidSeed_Basic(t *testing.T) {
@@ -14,10 +18,11 @@ func TestFindValidSeed_Basic(t *testing.T) {
 		playerGr  float64
 		enemyMat  [][]float64
 		enemyGr   float64
+		rngSeed   int64
 	}{
-		{"basic", 5, [][]float64{{0, 0}, {0, 0}}, 0.5, [][]float64{{0, 0}, {0, 0}}, 0.5},
-		{"different seed", 5, [][]float64{{0, 0}, {0, 0}}, 0.5, [][]float64{{0, 0}, {0, 0}}, 0.5},
-		{"larger matrix", 5, [][]float64{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}, 0.5, [][]float64{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}, 0.5},
+		{"basic", 5, [][]float64{{0, 0}, {0, 0}}, 0.5, [][]float64{{0, 0}, {0, 0}}, 0.5, 1},
+		{"different seed", 5, [][]float64{{0, 0}, {0, 0}}, 0.5, [][]float64{{0, 0}, {0, 0}}, 0.5, 2},
+		{"larger matrix", 5, [][]float64{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}, 0.5, [][]float64{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}, 0.5, 3},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -30,7 +35,8 @@ func TestFindValidSeed_Basic(t *testing.T) {
 			}
 			player := domain.NewPlayer(pm, tt.playerGr)
 			enemy := domain.NewEnemy("Enemy", domain.NewMatrix(tt.enemyMat), tt.enemyGr)
-			seed, playerPath, enemyPath, err := FindValidSeed(tt.battleMax, player, enemy)
+			report, err := FindValidSeed(context.Background(), tt.battleMax, player, enemy, logic.NewSeedManagerWithFixedValue(tt.rngSeed), SeedSearchOptions{})
+			seed, playerPath, enemyPath := report.Seed, report.PlayerPath, report.EnemyPath
 			if err != nil {
 				t.Fatalf("FindValidSeed error: %v", err)
 			}
@@ -48,16 +54,30 @@ func TestFindValidSeed_Basic(t *testing.T) {
 }
 
 func TestFindValidSeed_GuardCases(t *testing.T) {
+	zero := func() (*domain.Player, *domain.Enemy) {
+		return domain.NewPlayer(domain.NewMatrix([][]float64{{0, 0}, {0, 0}}), 0.5), domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 0}, {0, 0}}), 0.5)
+	}
+	player, enemy := zero()
+	empty := domain.NewPlayer(domain.NewMatrix([][]float64{}), 0.5)
+	notFound := SeedSearchOptions{Workers: 4, MaxTries: 50}
 	tests := []struct {
 		name      string
 		battleMax int
 		player    *domain.Player
 		enemy     *domain.Enemy
+		rng       logic.RandomSource
+		opts      SeedSearchOptions
 		wantPanic bool
+		wantErr   bool
 	}{
-		{"zero battleMax", 0, domain.NewPlayer(domain.NewMatrix([][]float64{{0, 0}, {0, 0}}), 0.5), domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 0}, {0, 0}}), 0.5), true},
-		{"nil player", 5, nil, domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 0}, {0, 0}}), 0.5), true},
-		{"nil enemy", 5, domain.NewPlayer(domain.NewMatrix([][]float64{{0, 0}, {0, 0}}), 0.5), nil, true},
+		{"zero battleMax", 0, player, enemy, logic.NewSeedManagerWithFixedValue(1), SeedSearchOptions{}, true, false},
+		{"nil player", 5, nil, enemy, logic.NewSeedManagerWithFixedValue(1), SeedSearchOptions{}, true, false},
+		{"nil enemy", 5, player, nil, logic.NewSeedManagerWithFixedValue(1), SeedSearchOptions{}, true, false},
+		{"nil rng", 5, player, enemy, nil, SeedSearchOptions{}, true, false},
+		// 空行列では結果が常に 0 になりプレイヤーは勝てない
+		{"empty matrices", 1, empty, domain.NewEnemy("E", domain.NewMatrix([][]float64{}), 0.5), logic.NewSeedManagerWithFixedValue(1), notFound, false, true},
+		// プレイヤー行列が空だと敵行列があっても結果は 0 のまま
+		{"unwinnable config", 3, empty, enemy, logic.NewSeedManagerWithFixedValue(1), notFound, false, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -70,7 +90,10 @@ func TestFindValidSeed_GuardCases(t *testing.T) {
 					t.Errorf("Unexpected panic: %v", r)
 				}
 			}()
-			_, _, _, _ = FindValidSeed(tt.battleMax, tt.player, tt.enemy)
+			_, err := FindValidSeed(context.Background(), tt.battleMax, tt.player, tt.enemy, tt.rng, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FindValidSeed error = %v, want error %v", err, tt.wantErr)
+			}
 		})
 	}
 }
@@ -87,7 +110,8 @@ func TestFindValidSeed_EdgeCases(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, playerPath, enemyPath, err := FindValidSeed(tt.battleMax, tt.player, tt.enemy)
+			report, err := FindValidSeed(context.Background(), tt.battleMax, tt.player, tt.enemy, logic.NewSeedManagerWithFixedValue(1), SeedSearchOptions{})
+			playerPath, enemyPath := report.PlayerPath, report.EnemyPath
 			if err != nil {
 				t.Fatalf("FindValidSeed error: %v", err)
 			}
@@ -100,3 +124,474 @@ func TestFindValidSeed_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestFindValidSeed_Deterministic(t *testing.T) {
+	tests := []struct {
+		name       string
+		battleMax  int
+		masterSeed int64
+	}{
+		{"master seed 1", 3, 1},
+		{"master seed 99", 4, 99},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := func() SeedReport {
+				player := domain.NewPlayer(domain.NewMatrix([][]float64{{2, 0}, {0, 2}}), 0.5)
+				enemy := domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 2}, {2, 0}}), 0.5)
+				report, err := FindValidSeed(context.Background(), tt.battleMax, player, enemy, logic.NewSeedManagerWithFixedValue(tt.masterSeed), SeedSearchOptions{})
+				if err != nil {
+					t.Fatalf("FindValidSeed error: %v", err)
+				}
+				return report
+			}
+			r1, r2 := run(), run()
+			if !reflect.DeepEqual(r1, r2) {
+				t.Errorf("FindValidSeed not deterministic: %+v vs %+v", r1, r2)
+			}
+		})
+	}
+}
+
+func TestCheckSeed(t *testing.T) {
+	newPair := func() (*domain.Player, *domain.Enemy) {
+		return domain.NewPlayer(domain.NewMatrix([][]float64{{2, 0}, {0, 2}}), 0.5),
+			domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 2}, {2, 0}}), 0.5)
+	}
+	player, enemy := newPair()
+	found, err := FindValidSeed(context.Background(), 3, player, enemy, logic.NewSeedManagerWithFixedValue(5), SeedSearchOptions{})
+	if err != nil {
+		t.Fatalf("FindValidSeed error: %v", err)
+	}
+	tests := []struct {
+		name      string
+		seed      int64
+		battleMax int
+		wantOK    bool
+	}{
+		{"seed found by FindValidSeed", found.Seed, 3, true},
+		{"empty matrices never win", 1, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := newPair()
+			if !tt.wantOK {
+				player = domain.NewPlayer(domain.NewMatrix([][]float64{}), 0.5)
+			}
+			report, ok := CheckSeed(tt.seed, tt.battleMax, player, enemy)
+			if ok != tt.wantOK {
+				t.Fatalf("CheckSeed ok = %v, want %v", ok, tt.wantOK)
+			}
+			if report.Seed != tt.seed || report.Tries != 1 {
+				t.Errorf("report seed/tries = %d/%d, want %d/1", report.Seed, report.Tries, tt.seed)
+			}
+			if ok && (len(report.PlayerPath) != tt.battleMax || len(report.EnemyPath) != tt.battleMax) {
+				t.Errorf("path lengths = %d/%d, want %d", len(report.PlayerPath), len(report.EnemyPath), tt.battleMax)
+			}
+			if ok && !reflect.DeepEqual(report.PlayerPath, found.PlayerPath) {
+				t.Errorf("CheckSeed path %v differs from FindValidSeed path %v", report.PlayerPath, found.PlayerPath)
+			}
+			if !ok && (report.PlayerPath != nil || report.EnemyPath != nil) {
+				t.Error("paths should be nil for rejected seed")
+			}
+		})
+	}
+}
+
+func TestCheckSeed_GuardCases(t *testing.T) {
+	player := domain.NewPlayer(domain.NewMatrix([][]float64{{0, 0}, {0, 0}}), 0.5)
+	enemy := domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 0}, {0, 0}}), 0.5)
+	tests := []struct {
+		name      string
+		battleMax int
+		player    *domain.Player
+		enemy     *domain.Enemy
+	}{
+		{"zero battleMax", 0, player, enemy},
+		{"nil player", 5, nil, enemy},
+		{"nil enemy", 5, player, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic but did not panic")
+				}
+			}()
+			CheckSeed(1, tt.battleMax, tt.player, tt.enemy)
+		})
+	}
+}
+
+func TestFindValidSeed_ParallelMatchesSerial(t *testing.T) {
+	tests := []struct {
+		name       string
+		battleMax  int
+		masterSeed int64
+		workers    int
+	}{
+		{"2 workers", 3, 1, 2},
+		{"8 workers", 4, 7, 8},
+		{"zero workers uses GOMAXPROCS", 3, 11, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := domain.NewPlayer(domain.NewMatrix([][]float64{{2, 0}, {0, 2}}), 0.5)
+			enemy := domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 2}, {2, 0}}), 0.5)
+			before := player.MatrixState.Copy()
+			r1, err1 := FindValidSeed(context.Background(), tt.battleMax, player, enemy, logic.NewSeedManagerWithFixedValue(tt.masterSeed), SeedSearchOptions{Workers: 1})
+			r2, err2 := FindValidSeed(context.Background(), tt.battleMax, player, enemy, logic.NewSeedManagerWithFixedValue(tt.masterSeed), SeedSearchOptions{Workers: tt.workers})
+			if err1 != nil || err2 != nil {
+				t.Fatalf("errors: %v / %v", err1, err2)
+			}
+			if !reflect.DeepEqual(r1, r2) {
+				t.Errorf("parallel result differs: %+v vs %+v", r1, r2)
+			}
+			if !reflect.DeepEqual(player.MatrixState, before) {
+				t.Error("FindValidSeed should not mutate the given player")
+			}
+			if _, ok := CheckSeed(r2.Seed, tt.battleMax, player, enemy); !ok {
+				t.Errorf("CheckSeed(%d) should accept the found seed", r2.Seed)
+			}
+		})
+	}
+}
+
+func TestFindValidSeed_Cancellation(t *testing.T) {
+	newPair := func() (*domain.Player, *domain.Enemy) {
+		return domain.NewPlayer(domain.NewMatrix([][]float64{}), 0.5), domain.NewEnemy("E", domain.NewMatrix([][]float64{}), 0.5)
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), 0)
+	defer cancelExpired()
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		cancelAfter int // Progress で cancel するまでの評価数（0 なら使わない）
+		want        error
+	}{
+		{"already cancelled", cancelled, 0, context.Canceled},
+		{"deadline exceeded", expired, 0, context.DeadlineExceeded},
+		{"cancelled from progress", context.Background(), 3, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(tt.ctx)
+			defer cancel()
+			tried := 0
+			opts := SeedSearchOptions{Workers: 1, Progress: func(p SeedProgress) {
+				tried = p.Tried
+				if tt.cancelAfter > 0 && p.Tried >= tt.cancelAfter {
+					cancel()
+				}
+			}}
+			player, enemy := newPair()
+			_, err := FindValidSeed(ctx, 3, player, enemy, logic.NewSeedManagerWithFixedValue(1), opts)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("FindValidSeed error = %v, want %v", err, tt.want)
+			}
+			if tried != tt.cancelAfter {
+				t.Errorf("tried = %d, want %d", tried, tt.cancelAfter)
+			}
+		})
+	}
+}
+
+// キャンセル済みの ctx では評価の途中のループもすぐ抜ける
+func TestSeedEvaluator_Cancelled(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name string
+		opts SeedSearchOptions
+	}{
+		{"sequential sampling", SeedSearchOptions{Sampling: SamplingSequential}},
+		{"fixed sampling", SeedSearchOptions{Sampling: SamplingFixed}},
+		{"dfs proof", SeedSearchOptions{Proof: ProofOptions{Strategy: ProofDFS}}},
+		{"mcts proof", SeedSearchOptions{Proof: ProofOptions{Strategy: ProofMCTS}}},
+		{"coordinate proof", SeedSearchOptions{Granularity: InputContinuous}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := DefaultGameConfig().NewCombatants()
+			e := newSeedEvaluator(10, player, enemy, DefaultSeedCriteria(), tt.opts)
+			stage, r := e.evaluate(cancelled, FallbackSeed)
+			if stage == stageAccepted || r.Simulations != 0 {
+				t.Errorf("evaluate = %v after %d simulations, want a rejection without simulating", stage, r.Simulations)
+			}
+			// 証明フェーズも根より先に進まない
+			if proof := e.proofPhase(NewRuleForSeed(FallbackSeed, e.size)); proof.ok || proof.nodes > 1 {
+				t.Errorf("proofPhase explored %d nodes (ok %v) after cancel", proof.nodes, proof.ok)
+			}
+			// 品質分析も初手ごとのサンプリングをしない
+			if q, err := e.analyzeQuality(FallbackSeed, NewRuleForSeed(FallbackSeed, e.size), nil); !errors.Is(err, context.Canceled) || q.FirstMoveEntropy != 0 || q.LateSensitivity != 0 {
+				t.Errorf("analyzeQuality sampled after cancel: %+v, %v", q, err)
+			}
+		})
+	}
+}
+
+// cancelAfterChecks: Err を left 回呼ばれるまでは動き、その後はキャンセル済みになる ctx
+type cancelAfterChecks struct {
+	context.Context
+	left int
+}
+
+func (c *cancelAfterChecks) Err() error {
+	if c.left <= 0 {
+		return context.Canceled
+	}
+	c.left--
+	return nil
+}
+
+// 評価の途中でキャンセルされると、各ループはその場で抜けて ctx.Err() を返す
+func TestSeedEvaluator_CancelledMidLoop(t *testing.T) {
+	rule := NewRuleForSeed(FallbackSeed, 3)
+	winning := digitInputs([]int{3, 7, 0, 1, 2, 3, 4, 5, 6, 9})
+	// 品質分析: 同じ入力の繰り返し 10 回、初手ごとのサンプリング 10 × qualitySamples 回の順に確かめる
+	const trivialChecks, firstMoveChecks = 10, 10 * 90
+	tests := []struct {
+		name   string
+		checks int // キャンセルまでに通る確認の回数
+		run    func(e *seedEvaluator) error
+	}{
+		{"constrained path from known paths", 0, func(e *seedEvaluator) error {
+			_, _, err := e.constrainedPath(rule, [][]float64{winning})
+			return err
+		}},
+		{"constrained path sampling", 0, func(e *seedEvaluator) error {
+			_, _, err := e.constrainedPath(rule, nil)
+			return err
+		}},
+		{"winning first moves", 0, func(e *seedEvaluator) error {
+			_, err := e.winningFirstMoves(rule, nil)
+			return err
+		}},
+		{"quality trivial inputs", 0, func(e *seedEvaluator) error {
+			_, err := e.analyzeQuality(FallbackSeed, rule, nil)
+			return err
+		}},
+		{"quality first moves", trivialChecks, func(e *seedEvaluator) error {
+			_, err := e.analyzeQuality(FallbackSeed, rule, nil)
+			return err
+		}},
+		{"quality exact sequence", trivialChecks + firstMoveChecks, func(e *seedEvaluator) error {
+			_, err := e.analyzeQuality(FallbackSeed, rule, winning)
+			return err
+		}},
+		{"quality late sensitivity", trivialChecks + firstMoveChecks, func(e *seedEvaluator) error {
+			_, err := e.analyzeQuality(FallbackSeed, rule, nil)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := DefaultGameConfig().NewCombatants()
+			e := newSeedEvaluator(10, player, enemy, DefaultSeedCriteria(), SeedSearchOptions{})
+			ctx := &cancelAfterChecks{Context: context.Background(), left: tt.checks}
+			e.ctx = ctx
+			if err := tt.run(e); !errors.Is(err, context.Canceled) {
+				t.Fatalf("err = %v, want %v", err, context.Canceled)
+			}
+			if ctx.left != 0 {
+				t.Errorf("%d checks left, the loop stopped before the cancellation", ctx.left)
+			}
+		})
+	}
+}
+
+func TestFindValidSeed_Progress(t *testing.T) {
+	tests := []struct {
+		name      string
+		playerMat [][]float64
+		enemyMat  [][]float64
+		maxTries  int
+		wantFound bool
+	}{
+		{"empty matrices reject every candidate", [][]float64{}, [][]float64{}, 20, false},
+		{"normal matrices find a seed", [][]float64{{2, 0}, {0, 2}}, [][]float64{{0, 2}, {2, 0}}, 1000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var last SeedProgress
+			calls := 0
+			opts := SeedSearchOptions{Workers: 2, MaxTries: tt.maxTries, Progress: func(p SeedProgress) {
+				calls++
+				if p.Tried != last.Tried+1 {
+					t.Errorf("Tried jumped from %d to %d", last.Tried, p.Tried)
+				}
+				last = p
+			}}
+			player := domain.NewPlayer(domain.NewMatrix(tt.playerMat), 0.5)
+			enemy := domain.NewEnemy("E", domain.NewMatrix(tt.enemyMat), 0.5)
+			_, err := FindValidSeed(context.Background(), 3, player, enemy, logic.NewSeedManagerWithFixedValue(3), opts)
+			if (err == nil) != tt.wantFound {
+				t.Fatalf("FindValidSeed error = %v, wantFound %v", err, tt.wantFound)
+			}
+			rejected := last.RoughRejected + last.DeepRejected + last.ProofRejected
+			if !tt.wantFound && (last.Tried != tt.maxTries || rejected != tt.maxTries) {
+				t.Errorf("progress = %+v, want all %d candidates rejected", last, tt.maxTries)
+			}
+			if tt.wantFound && (!last.HasBest || rejected >= last.Tried) {
+				t.Errorf("progress = %+v, want a best candidate and an accepted one", last)
+			}
+			if calls != last.Tried {
+				t.Errorf("Progress called %d times, Tried = %d", calls, last.Tried)
+			}
+		})
+	}
+}
+
+func TestFindValidSeed_Report(t *testing.T) {
+	tests := []struct {
+		name       string
+		battleMax  int
+		masterSeed int64
+	}{
+		{"3 battles", 3, 21},
+		{"5 battles", 5, 22},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := domain.NewPlayer(domain.NewMatrix([][]float64{{2, 0}, {0, 2}}), 0.5)
+			enemy := domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 2}, {2, 0}}), 0.5)
+			r, err := FindValidSeed(context.Background(), tt.battleMax, player, enemy, logic.NewSeedManagerWithFixedValue(tt.masterSeed), SeedSearchOptions{})
+			if err != nil {
+				t.Fatalf("FindValidSeed error: %v", err)
+			}
+			// 逐次サンプリングでは RoughFilter 200 + DeepFilter 800 回が上限
+			if r.Rough.Samples == 0 || r.Rough.Samples > r.Deep.Samples || r.Deep.Samples > 1000 || r.Simulations != r.Deep.Samples {
+				t.Errorf("samples = %d/%d (simulations %d), want 0 < rough <= deep = simulations <= 1000", r.Rough.Samples, r.Deep.Samples, r.Simulations)
+			}
+			if r.Deep.Rate <= 0 || r.Deep.Rate >= 1 || r.Deep.Low > r.Deep.Rate || r.Deep.High < r.Deep.Rate {
+				t.Errorf("deep estimate inconsistent: %+v", r.Deep)
+			}
+			if r.PlayerWinLeaves == 0 || r.EnemyWinLeaves == 0 || r.NodesExplored < r.PlayerWinLeaves+r.EnemyWinLeaves {
+				t.Errorf("proof counts inconsistent: %+v", r)
+			}
+			if r.Tries < 1 {
+				t.Errorf("Tries = %d, want >= 1", r.Tries)
+			}
+			if got := r.Rating(); got != 1-r.Deep.Rate {
+				t.Errorf("Rating = %v, want %v", got, 1-r.Deep.Rate)
+			}
+			if r.Summary() == "" {
+				t.Error("Summary should not be empty")
+			}
+		})
+	}
+}
+
+func TestFindSeed_Difficulty(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Difficulty
+	}{
+		{"easy", DifficultyEasy},
+		{"normal", DifficultyNormal},
+		{"hard", DifficultyHard},
+		{"expert", DifficultyExpert},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := domain.NewPlayer(domain.NewMatrix([][]float64{{2, 0}, {0, 2}}), 0.5)
+			enemy := domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 2}, {2, 0}}), 0.5)
+			c := CriteriaForDifficulty(tt.d)
+			r, err := FindSeed(context.Background(), 5, player, enemy, logic.NewSeedManagerWithFixedValue(31), c, SeedSearchOptions{})
+			if err != nil {
+				t.Fatalf("FindSeed error: %v", err)
+			}
+			if r.Difficulty() != tt.d {
+				t.Errorf("Difficulty = %v (rate %v), want %v", r.Difficulty(), r.Deep.Rate, tt.d)
+			}
+			if r.WinningFirstMoves < c.MinWinningFirstMoves || r.DecisiveDepth < min(c.MinDecisiveDepth, 5) {
+				t.Errorf("first moves/depth = %d/%d, want >= %d/%d", r.WinningFirstMoves, r.DecisiveDepth, c.MinWinningFirstMoves, c.MinDecisiveDepth)
+			}
+		})
+	}
+}
+
+func TestFindSeed_Criteria(t *testing.T) {
+	tests := []struct {
+		name      string
+		criteria  SeedCriteria
+		wantPanic bool
+		wantFound bool
+	}{
+		{"default matches FindValidSeed", DefaultSeedCriteria(), false, true},
+		{"more first moves than inputs", SeedCriteria{MaxWinRate: 1, MinWinningFirstMoves: 11}, false, false},
+		{"invalid band", SeedCriteria{MinWinRate: 0.5, MaxWinRate: 0.2}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("panic = %v, wantPanic %v", r, tt.wantPanic)
+				}
+			}()
+			player := domain.NewPlayer(domain.NewMatrix([][]float64{{2, 0}, {0, 2}}), 0.5)
+			enemy := domain.NewEnemy("E", domain.NewMatrix([][]float64{{0, 2}, {2, 0}}), 0.5)
+			opts := SeedSearchOptions{MaxTries: 30}
+			r, err := FindSeed(context.Background(), 3, player, enemy, logic.NewSeedManagerWithFixedValue(8), tt.criteria, opts)
+			if (err == nil) != tt.wantFound {
+				t.Fatalf("FindSeed error = %v, wantFound %v", err, tt.wantFound)
+			}
+			if !tt.wantFound {
+				return
+			}
+			want, err := FindValidSeed(context.Background(), 3, player, enemy, logic.NewSeedManagerWithFixedValue(8), opts)
+			if err != nil || !reflect.DeepEqual(r, want) {
+				t.Errorf("FindSeed(default) = %+v, FindValidSeed = %+v (%v)", r, want, err)
+			}
+		})
+	}
+}
+
+func TestFindSeed_Granularity(t *testing.T) {
+	config := smallBankConfig()
+	config.BattleMax = 4
+	for _, g := range []InputGranularity{InputPercent, InputContinuous} {
+		t.Run(g.String(), func(t *testing.T) {
+			player, enemy := config.NewCombatants()
+			opts := SeedSearchOptions{Granularity: g, Workers: 1}
+			r, err := FindValidSeed(context.Background(), config.BattleMax, player, enemy, logic.NewSeedManagerWithFixedValue(3), opts)
+			if err != nil {
+				t.Fatalf("FindValidSeed error: %v", err)
+			}
+			// 座標探索の結果は入力値のみで、粒度の格子上にあり、再生すると勝敗が一致する
+			if r.PlayerPath != nil || r.EnemyPath != nil {
+				t.Errorf("digit paths should be empty: %v / %v", r.PlayerPath, r.EnemyPath)
+			}
+			e := newSeedEvaluator(config.BattleMax, player, enemy, DefaultSeedCriteria(), opts)
+			rule := NewRuleForSeed(r.Seed, config.Size())
+			for _, side := range []struct {
+				inputs []float64
+				win    bool
+			}{{r.PlayerInputs, true}, {r.EnemyInputs, false}} {
+				if len(side.inputs) != config.BattleMax {
+					t.Fatalf("inputs %v, want %d turns", side.inputs, config.BattleMax)
+				}
+				for _, x := range side.inputs {
+					if g.Quantize(x) != x {
+						t.Errorf("input %v is off the %s grid", x, g)
+					}
+				}
+				if _, win := e.playInputs(rule, side.inputs); win != side.win {
+					t.Errorf("inputs %v replay win=%v, want %v", side.inputs, win, side.win)
+				}
+			}
+			if r.DecisiveDepth < 1 || r.WinningFirstMoves < 1 || r.NodesExplored < r.PlayerWinLeaves+r.EnemyWinLeaves {
+				t.Errorf("proof statistics inconsistent: %+v", r)
+			}
+
+			// 並列でも同じ候補が選ばれる
+			opts.Workers = 4
+			again, err := FindValidSeed(context.Background(), config.BattleMax, player, enemy, logic.NewSeedManagerWithFixedValue(3), opts)
+			if err != nil || !reflect.DeepEqual(again, r) {
+				t.Errorf("parallel search differs: %+v vs %+v (%v)", again, r, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WinRateEstimate is a sampled player win rate with its 99% Wilson interval.
+type WinRateEstimate struct {
+	Wins    int     `json:"wins"`
+	Samples int     `json:"samples"`
+	Rate    float64 `json:"rate"`
+	Low     float64 `json:"low"`
+	High    float64 `json:"high"`
+}
+
+func newWinRateEstimate(wins, samples int) WinRateEstimate {
+	low, high := wilsonInterval(wins, samples)
+	rate := 0.0
+	if samples > 0 {
+		rate = float64(wins) / float64(samples)
+	}
+	return WinRateEstimate{Wins: wins, Samples: samples, Rate: rate, Low: low, High: high}
+}
+
+// Difficulty is a coarse hardness level derived from the random-play win rate.
+// DifficultyAny (0) means "unspecified" in share codes.
+type Difficulty int
+
+const (
+	DifficultyAny Difficulty = iota
+	DifficultyEasy
+	DifficultyNormal
+	DifficultyHard
+	DifficultyExpert
+)
+
+// String returns the display name of the difficulty.
+func (d Difficulty) String() string {
+	switch d {
+	case DifficultyEasy:
+		return "Easy"
+	case DifficultyNormal:
+		return "Normal"
+	case DifficultyHard:
+		return "Hard"
+	case DifficultyExpert:
+		return "Expert"
+	}
+	return "Any"
+}
+
+// ParseDifficulty parses a difficulty name as returned by String (case-insensitive).
+func ParseDifficulty(s string) (Difficulty, error) {
+	for d := DifficultyAny; d <= DifficultyExpert; d++ {
+		if strings.EqualFold(s, d.String()) {
+			return d, nil
+		}
+	}
+	return DifficultyAny, fmt.Errorf("unknown difficulty %q (want any, easy, normal, hard or expert)", s)
+}
+
+// DifficultyForWinRate maps a random-play win rate to a difficulty level.
+func DifficultyForWinRate(rate float64) Difficulty {
+	switch {
+	case rate >= 0.5:
+		return DifficultyEasy
+	case rate >= 0.25:
+		return DifficultyNormal
+	case rate >= 0.1:
+		return DifficultyHard
+	}
+	return DifficultyExpert
+}
+
+// SeedReport explains what the seed finder learned about a seed.
+type SeedReport struct {
+	Seed       int64 `json:"seed"`
+	PlayerPath []int `json:"player_path"` // プレイヤーが勝つ入力列の一例
+	EnemyPath  []int `json:"enemy_path"`  // 敵が勝つ入力列の一例
+	// PlayerPath / EnemyPath を [0, 1] の入力値で表したもの。10 キー以外の粒度で探索した場合はこちらのみ埋まる
+	PlayerInputs []float64 `json:"player_inputs"`
+	EnemyInputs  []float64 `json:"enemy_inputs"`
+
+	Rough       WinRateEstimate `json:"rough"`       // RoughFilter のランダムプレイ勝率
+	Deep        WinRateEstimate `json:"deep"`        // DeepFilter のランダムプレイ勝率
+	Simulations int             `json:"simulations"` // RoughFilter / DeepFilter で行ったランダムプレイの回数
+
+	PlayerWinLeaves int `json:"player_win_leaves"` // ProofPhase で見つかったプレイヤー勝利の末端数
+	EnemyWinLeaves  int `json:"enemy_win_leaves"`  // ProofPhase で見つかった敵勝利の末端数
+	NodesExplored   int `json:"nodes_explored"`    // ProofPhase で展開したノード数
+
+	WinningFirstMoves int `json:"winning_first_moves"` // 勝ちに繋がる異なる初手の数
+	DecisiveDepth     int `json:"decisive_depth"`      // 勝敗がまだ分岐しうる最も深いターン（1 始まり）
+
+	Quality SeedQuality `json:"quality"` // 品質分析の結果（ProofPhase を通過した seed のみ）
+
+	Tries int `json:"tries"` // 受理までに評価した候補数（CheckSeed では 1）
+}
+
+// WinningInputs returns the known player-winning inputs in [0, 1]:
+// PlayerInputs, or PlayerPath converted from digit keys for reports saved before inputs were recorded.
+// It returns nil if the report has no winning path.
+func (r SeedReport) WinningInputs() []float64 {
+	if r.PlayerInputs != nil {
+		return r.PlayerInputs
+	}
+	if r.PlayerPath != nil {
+		return digitInputs(r.PlayerPath)
+	}
+	return nil
+}
+
+// Rating returns the difficulty rating in [0, 1]: 1 minus the deep random-play win rate.
+func (r SeedReport) Rating() float64 {
+	return 1 - r.Deep.Rate
+}
+
+// Difficulty returns the difficulty level of the seed.
+func (r SeedReport) Difficulty() Difficulty {
+	return DifficultyForWinRate(r.Deep.Rate)
+}
+
+// Summary returns a one-line human readable explanation of the report.
+func (r SeedReport) Summary() string {
+	return fmt.Sprintf("%s (random win %.0f%% [%.0f-%.0f%%], proof %d/%d leaves, %d nodes, %d tries)",
+		r.Difficulty(), r.Deep.Rate*100, r.Deep.Low*100, r.Deep.High*100,
+		r.PlayerWinLeaves, r.EnemyWinLeaves, r.NodesExplored, r.Tries)
+}
+
+// SortReportsByDifficulty orders reports from easiest to hardest (ties by seed).
+func SortReportsByDifficulty(reports []SeedReport) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		if reports[i].Rating() != reports[j].Rating() {
+			return reports[i].Rating() < reports[j].Rating()
+		}
+		return reports[i].Seed < reports[j].Seed
+	})
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewWinRateEstimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		wins     int
+		samples  int
+		wantRate float64
+	}{
+		{"no samples", 0, 0, 0},
+		{"all lose", 0, 100, 0},
+		{"half", 50, 100, 0.5},
+		{"all win", 100, 100, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newWinRateEstimate(tt.wins, tt.samples)
+			if e.Rate != tt.wantRate || e.Wins != tt.wins || e.Samples != tt.samples {
+				t.Errorf("estimate = %+v, want rate %v", e, tt.wantRate)
+			}
+			if e.Low < 0 || e.High > 1 || e.Low > e.Rate+1e-9 || e.High < e.Rate-1e-9 {
+				t.Errorf("interval [%v, %v] does not contain %v", e.Low, e.High, e.Rate)
+			}
+		})
+	}
+}
+
+func TestDifficultyForWinRate(t *testing.T) {
+	tests := []struct {
+		rate float64
+		want Difficulty
+		name string
+	}{
+		{0.8, DifficultyEasy, "Easy"},
+		{0.5, DifficultyEasy, "Easy"},
+		{0.3, DifficultyNormal, "Normal"},
+		{0.1, DifficultyHard, "Hard"},
+		{0.02, DifficultyExpert, "Expert"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DifficultyForWinRate(tt.rate)
+			if got != tt.want || got.String() != tt.name {
+				t.Errorf("DifficultyForWinRate(%v) = %v, want %v", tt.rate, got, tt.want)
+			}
+			if r := (SeedReport{Deep: WinRateEstimate{Rate: tt.rate}}); r.Difficulty() != tt.want {
+				t.Errorf("SeedReport.Difficulty = %v, want %v", r.Difficulty(), tt.want)
+			}
+		})
+	}
+	if DifficultyAny.String() != "Any" {
+		t.Errorf("DifficultyAny.String() = %q", DifficultyAny.String())
+	}
+}
+
+func TestSortReportsByDifficulty(t *testing.T) {
+	tests := []struct {
+		name  string
+		rates []float64
+		seeds []int64
+		want  []int64
+	}{
+		{"easiest first", []float64{0.1, 0.6, 0.3}, []int64{1, 2, 3}, []int64{2, 3, 1}},
+		{"ties by seed", []float64{0.2, 0.2}, []int64{9, 4}, []int64{4, 9}},
+		{"empty", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reports []SeedReport
+			for i, rate := range tt.rates {
+				reports = append(reports, SeedReport{Seed: tt.seeds[i], Deep: WinRateEstimate{Rate: rate}})
+			}
+			SortReportsByDifficulty(reports)
+			var got []int64
+			for _, r := range reports {
+				got = append(got, r.Seed)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDifficulty(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Difficulty
+		wantErr bool
+	}{
+		{"any", DifficultyAny, false},
+		{"Easy", DifficultyEasy, false},
+		{"NORMAL", DifficultyNormal, false},
+		{"hard", DifficultyHard, false},
+		{"expert", DifficultyExpert, false},
+		{"insane", DifficultyAny, true},
+		{"", DifficultyAny, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDifficulty(tt.in)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("ParseDifficulty(%q) = %v, %v, want %v, err %v", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSeedReport_WinningInputs(t *testing.T) {
+	tests := []struct {
+		name   string
+		report SeedReport
+		want   []float64
+	}{
+		{"inputs", SeedReport{PlayerPath: []int{9}, PlayerInputs: []float64{0.25}}, []float64{0.25}},
+		{"digit path only", SeedReport{PlayerPath: []int{0, 9, 3}}, []float64{0, 1, 3.0 / 9}},
+		{"none", SeedReport{Seed: 1}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.WinningInputs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WinningInputs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
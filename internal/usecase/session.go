@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"axiom_shift/internal/domain"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrGameOver is returned when an input or confirmation arrives after the last battle.
+	ErrGameOver = errors.New("game is over")
+	// ErrNoInput is returned by Confirm when no input has been submitted for the turn.
+	ErrNoInput = errors.New("no input submitted")
+)
+
+// TurnRecord is the outcome of one battle of a session.
+type TurnRecord struct {
+	Battle int     `json:"battle"` // 1 始まり
+	Input  float64 `json:"input"`
+	Result float64 `json:"result"`
+	Win    bool    `json:"win"`
+}
+
+// SessionResult summarizes a session so far.
+type SessionResult struct {
+	Seed      int64        `json:"seed"`
+	BattleMax int          `json:"battle_max"`
+	Turns     []TurnRecord `json:"turns"`
+	Over      bool         `json:"over"` // 最終戦まで終わったか
+	Win       bool         `json:"win"`  // 最終戦の勝敗（Over のときのみ意味を持つ）
+}
+
+// GameSession runs the turn flow of one game on a fixed seed: the player submits an input,
+// confirms it to fight the battle, and the last battle decides the game.
+// It owns the battle count, the history and the outcome; front ends only translate keys and draw.
+type GameSession struct {
+	seed        int64
+	battleMax   int
+	granularity InputGranularity
+	player      *domain.Player
+	enemy       *domain.Enemy
+	rule        *domain.RuleMatrix
+	turns       []TurnRecord
+	pending     float64
+	phase       Phase // PhaseInput・PhaseConfirm・PhaseEnd のいずれか
+}
+
+// NewGameSession starts a session on seed. The session owns player and enemy
+// (resets them at start and on Retry) so front ends can draw their matrices.
+func NewGameSession(seed int64, battleMax int, player *domain.Player, enemy *domain.Enemy, granularity InputGranularity) *GameSession {
+	if battleMax <= 0 || player == nil || enemy == nil {
+		panic("Invalid parameters: battleMax must be > 0, player and enemy must not be nil")
+	}
+	s := &GameSession{
+		seed:        seed,
+		battleMax:   battleMax,
+		granularity: granularity,
+		player:      player,
+		enemy:       enemy,
+		rule:        NewRuleForSeed(seed, matrixSize(player)),
+	}
+	s.Retry()
+	return s
+}
+
+// SubmitInput sets the input for the current turn, quantized to the session granularity.
+// Submitting again before Confirm replaces it.
+func (s *GameSession) SubmitInput(x float64) error {
+	if s.Over() {
+		return ErrGameOver
+	}
+	if math.IsNaN(x) || x < 0 || x > 1 {
+		return fmt.Errorf("input %v must be in [0, 1]", x)
+	}
+	s.pending = s.granularity.Quantize(x)
+	s.phase = PhaseConfirm
+	return nil
+}
+
+// CancelInput discards the submitted input so another can be chosen.
+func (s *GameSession) CancelInput() {
+	if s.phase == PhaseConfirm {
+		s.phase = PhaseInput
+	}
+}
+
+// Pending returns the submitted input awaiting Confirm.
+func (s *GameSession) Pending() (float64, bool) {
+	return s.pending, s.phase == PhaseConfirm
+}
+
+// Phase returns where the session is in the turn flow: PhaseInput while waiting for an input,
+// PhaseConfirm once one is submitted and PhaseEnd after the last battle.
+func (s *GameSession) Phase() Phase {
+	return s.phase
+}
+
+// Confirm fights the current battle with the submitted input and returns its record.
+func (s *GameSession) Confirm() (TurnRecord, error) {
+	if s.Over() {
+		return TurnRecord{}, ErrGameOver
+	}
+	if s.phase != PhaseConfirm {
+		return TurnRecord{}, ErrNoInput
+	}
+	battle := len(s.turns)
+	result, win := NewBattleService(s.player, s.enemy, s.rule).DoBattleTurn(s.pending, battle)
+	record := TurnRecord{Battle: battle + 1, Input: s.pending, Result: result, Win: win}
+	s.turns = append(s.turns, record)
+	s.phase = PhaseInput
+	if len(s.turns) >= s.battleMax {
+		s.phase = PhaseEnd
+	}
+	return record, nil
+}
+
+// Retry restarts the session on the same seed.
+func (s *GameSession) Retry() {
+	s.player.Reset()
+	s.enemy.Reset()
+	s.turns = nil
+	s.phase = PhaseInput
+}
+
+// Result returns the turns played so far and, once over, the outcome.
+func (s *GameSession) Result() SessionResult {
+	r := SessionResult{
+		Seed:      s.seed,
+		BattleMax: s.battleMax,
+		Turns:     append([]TurnRecord(nil), s.turns...),
+		Over:      s.Over(),
+	}
+	if r.Over {
+		r.Win = s.turns[len(s.turns)-1].Win
+	}
+	return r
+}
+
+// Over reports whether the last battle has been fought.
+func (s *GameSession) Over() bool {
+	return s.phase == PhaseEnd
+}
+
+// BattleCount returns the number of battles fought.
+func (s *GameSession) BattleCount() int {
+	return len(s.turns)
+}
+
+// BattleMax returns the number of battles of the game.
+func (s *GameSession) BattleMax() int {
+	return s.battleMax
+}
+
+// Seed returns the seed of the rule.
+func (s *GameSession) Seed() int64 {
+	return s.seed
+}
+
+// Granularity returns the input granularity of the session.
+func (s *GameSession) Granularity() InputGranularity {
+	return s.granularity
+}
+
+// Inputs returns the confirmed inputs so far.
+func (s *GameSession) Inputs() []float64 {
+	inputs := make([]float64, len(s.turns))
+	for i, t := range s.turns {
+		inputs[i] = t.Input
+	}
+	return inputs
+}
+
+// LastTurn returns the most recent battle, if any.
+func (s *GameSession) LastTurn() (TurnRecord, bool) {
+	if len(s.turns) == 0 {
+		return TurnRecord{}, false
+	}
+	return s.turns[len(s.turns)-1], true
+}
+
+// Player returns the player whose matrix reflects the battles so far.
+func (s *GameSession) Player() *domain.Player {
+	return s.player
+}
+
+// Enemy returns the enemy whose matrix reflects the battles so far.
+func (s *GameSession) Enemy() *domain.Enemy {
+	return s.enemy
+}
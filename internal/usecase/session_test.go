@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// sessionOp: テスト用のセッション操作 1 回分
+type sessionOp struct {
+	op      string // "submit", "cancel", "confirm", "retry"
+	x       float64
+	wantErr error // nil 以外なら errors.Is で比較。errAny は種類を問わずエラー
+	phase   Phase // 操作後の Phase
+}
+
+var errAny = errors.New("any error")
+
+func TestGameSession_Flow(t *testing.T) {
+	tests := []struct {
+		name        string
+		seed        int64
+		granularity InputGranularity
+		ops         []sessionOp
+		wantInputs  []float64 // 確定済みの入力
+		wantPending []float64 // 確認待ちの入力（無ければ nil）
+		wantOver    bool
+	}{
+		{"full game", 13, InputDigits, []sessionOp{
+			{op: "submit", x: 0, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+			{op: "submit", x: 1, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+			{op: "submit", x: 5.0 / 9, phase: PhaseConfirm}, {op: "confirm", phase: PhaseEnd},
+		}, []float64{0, 1, 5.0 / 9}, nil, true},
+		{"no ops", 13, InputDigits, nil, []float64{}, nil, false},
+		{"confirm without input", 13, InputDigits, []sessionOp{
+			{op: "confirm", wantErr: ErrNoInput, phase: PhaseInput},
+		}, []float64{}, nil, false},
+		{"resubmit replaces", 13, InputDigits, []sessionOp{
+			{op: "submit", x: 0, phase: PhaseConfirm}, {op: "submit", x: 1, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+		}, []float64{1}, nil, false},
+		{"cancel", 13, InputDigits, []sessionOp{
+			{op: "submit", x: 0, phase: PhaseConfirm}, {op: "cancel", phase: PhaseInput}, {op: "confirm", wantErr: ErrNoInput, phase: PhaseInput},
+		}, []float64{}, nil, false},
+		{"cancel without input", 13, InputDigits, []sessionOp{
+			{op: "cancel", phase: PhaseInput},
+		}, []float64{}, nil, false},
+		{"quantized to digits", 13, InputDigits, []sessionOp{
+			{op: "submit", x: 0.5, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+		}, []float64{5.0 / 9}, nil, false},
+		{"pending is quantized", 7, InputPercent, []sessionOp{
+			{op: "submit", x: 0.123, phase: PhaseConfirm},
+		}, []float64{}, []float64{InputPercent.Quantize(0.123)}, false},
+		{"continuous keeps value", 13, InputContinuous, []sessionOp{
+			{op: "submit", x: 0.3, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+		}, []float64{0.3}, nil, false},
+		{"out of range", 13, InputDigits, []sessionOp{
+			{op: "submit", x: 1.5, wantErr: errAny, phase: PhaseInput}, {op: "confirm", wantErr: ErrNoInput, phase: PhaseInput},
+		}, []float64{}, nil, false},
+		{"out of range keeps the pending input", 13, InputDigits, []sessionOp{
+			{op: "submit", x: 1, phase: PhaseConfirm}, {op: "submit", x: -1, wantErr: errAny, phase: PhaseConfirm},
+		}, []float64{}, []float64{1}, false},
+		{"after game over", 13, InputDigits, []sessionOp{
+			{op: "submit", x: 0, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+			{op: "submit", x: 0, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+			{op: "submit", x: 0, phase: PhaseConfirm}, {op: "confirm", phase: PhaseEnd},
+			{op: "submit", x: 0, wantErr: ErrGameOver, phase: PhaseEnd}, {op: "cancel", phase: PhaseEnd}, {op: "confirm", wantErr: ErrGameOver, phase: PhaseEnd},
+		}, []float64{0, 0, 0}, nil, true},
+		{"retry", 13, InputDigits, []sessionOp{
+			{op: "submit", x: 0, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+			{op: "submit", x: 1, phase: PhaseConfirm}, {op: "retry", phase: PhaseInput},
+			{op: "confirm", wantErr: ErrNoInput, phase: PhaseInput},
+			{op: "submit", x: 1, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+		}, []float64{1}, nil, false},
+		{"retry after game over", 13, InputDigits, []sessionOp{
+			{op: "submit", x: 0, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+			{op: "submit", x: 0, phase: PhaseConfirm}, {op: "confirm", phase: PhaseInput},
+			{op: "submit", x: 0, phase: PhaseConfirm}, {op: "confirm", phase: PhaseEnd},
+			{op: "retry", phase: PhaseInput},
+		}, []float64{}, nil, false},
+	}
+	config := smallBankConfig()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := config.NewCombatants()
+			s := NewGameSession(tt.seed, config.BattleMax, player, enemy, tt.granularity)
+			if s.Phase() != PhaseInput {
+				t.Fatalf("initial Phase = %s, want input", s.Phase())
+			}
+			for i, op := range tt.ops {
+				var err error
+				switch op.op {
+				case "submit":
+					err = s.SubmitInput(op.x)
+				case "cancel":
+					s.CancelInput()
+				case "confirm":
+					var turn TurnRecord
+					turn, err = s.Confirm()
+					if last, ok := s.LastTurn(); err == nil && (!ok || last != turn) {
+						t.Errorf("op %d: LastTurn = %+v, %v, want %+v", i, last, ok, turn)
+					}
+				case "retry":
+					s.Retry()
+				}
+				switch {
+				case op.wantErr == nil && err != nil:
+					t.Fatalf("op %d %s: unexpected error %v", i, op.op, err)
+				case op.wantErr == errAny && err == nil,
+					op.wantErr != nil && op.wantErr != errAny && !errors.Is(err, op.wantErr):
+					t.Fatalf("op %d %s: error = %v, want %v", i, op.op, err, op.wantErr)
+				}
+				if s.Phase() != op.phase {
+					t.Fatalf("op %d %s: Phase = %s, want %s", i, op.op, s.Phase(), op.phase)
+				}
+			}
+			if s.Seed() != tt.seed || s.BattleMax() != config.BattleMax || s.Granularity() != tt.granularity || s.Player() != player || s.Enemy() != enemy {
+				t.Errorf("accessors = %d %d %v", s.Seed(), s.BattleMax(), s.Granularity())
+			}
+			if got := s.Inputs(); !reflect.DeepEqual(got, tt.wantInputs) {
+				t.Errorf("Inputs = %v, want %v", got, tt.wantInputs)
+			}
+			if x, ok := s.Pending(); ok != (tt.wantPending != nil) || ok && x != tt.wantPending[0] {
+				t.Errorf("Pending = %v, %v, want %v", x, ok, tt.wantPending)
+			}
+			if s.BattleCount() != len(tt.wantInputs) || s.Over() != tt.wantOver {
+				t.Errorf("BattleCount = %d, Over = %v", s.BattleCount(), s.Over())
+			}
+			if _, ok := s.LastTurn(); ok != (len(tt.wantInputs) > 0) {
+				t.Errorf("LastTurn ok = %v with %d battles", ok, len(tt.wantInputs))
+			}
+			// 各ターンの結果は同じ入力の再生と一致する
+			steps := Replay(tt.seed, player, enemy, tt.wantInputs)
+			r := s.Result()
+			if r.Seed != tt.seed || r.BattleMax != config.BattleMax || r.Over != tt.wantOver || len(r.Turns) != len(steps) {
+				t.Fatalf("Result = %+v", r)
+			}
+			for i, turn := range r.Turns {
+				if turn.Battle != i+1 || turn.Input != steps[i].Input || turn.Result != steps[i].Result || turn.Win != steps[i].Win {
+					t.Errorf("turn %d = %+v, want %+v", i+1, turn, steps[i])
+				}
+			}
+			if tt.wantOver && r.Win != steps[len(steps)-1].Win {
+				t.Errorf("Win = %v, want the last battle's outcome", r.Win)
+			}
+			if !tt.wantOver && r.Win {
+				t.Error("Win should be false before the game is over")
+			}
+			// Result の Turns はコピー
+			if len(r.Turns) > 0 {
+				r.Turns[0].Input = -1
+				if s.Result().Turns[0].Input == -1 {
+					t.Error("Result shares its turns with the session")
+				}
+			}
+			// 行列は確定済みの入力を再生した状態（入力が無ければ Retry・開始時の初期状態）
+			wantPlayer, wantEnemy := config.PlayerMatrix, config.EnemyMatrix
+			if len(steps) > 0 {
+				wantPlayer, wantEnemy = steps[len(steps)-1].Player, steps[len(steps)-1].Enemy
+			}
+			if !reflect.DeepEqual(player.GetMatrix().Data, wantPlayer) || !reflect.DeepEqual(enemy.GetMatrix().Data, wantEnemy) {
+				t.Errorf("matrices %v / %v, want %v / %v", player.GetMatrix().Data, enemy.GetMatrix().Data, wantPlayer, wantEnemy)
+			}
+		})
+	}
+}
+
+func TestNewGameSession_Panics(t *testing.T) {
+	config := smallBankConfig()
+	player, enemy := config.NewCombatants()
+	tests := []struct {
+		name      string
+		battleMax int
+		nilPlayer bool
+	}{
+		{"zero battles", 0, false},
+		{"nil player", 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("NewGameSession should panic")
+				}
+			}()
+			p := player
+			if tt.nilPlayer {
+				p = nil
+			}
+			NewGameSession(1, tt.battleMax, p, enemy, InputDigits)
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package usecase
+
+import (
+	"axiom_shift/internal/domain"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// ErrSolveTooLarge is returned when the game tree does not fit the solver limits.
+var ErrSolveTooLarge = errors.New("configuration too large for the exact solver")
+
+// solveMaxBattles: 末端数 10^battleMax が int64 に収まる上限
+const solveMaxBattles = 18
+
+// solveDefaultMaxPaths: MaxPaths を指定しない場合に列挙する勝ちパスの上限
+const solveDefaultMaxPaths = 1000
+
+// solveBoundSlack: 上界の浮動小数点誤差の余裕（上界がこれだけ下回って初めて枝を刈る）
+const solveBoundSlack = 1e-9
+
+// SolveOptions tunes Solve. The zero value uses the defaults.
+type SolveOptions struct {
+	MaxStates int     // 転置表に登録する状態数の上限（0 以下なら 1<<20）。超えると ErrSolveTooLarge
+	MaxPaths  int     // WinningPaths に列挙する勝ちパスの上限（0 以下なら 1000）
+	Quantum   float64 // 状態を同一視する量子化の刻み（0 以下なら 1e-9）
+}
+
+// Solution is the exact answer for one seed: every input sequence is accounted for.
+type Solution struct {
+	Seed          int64
+	TotalPaths    int64   // 入力列の総数（10^battleMax）
+	WinningCount  int64   // プレイヤーが勝つ入力列の数
+	WinningPaths  [][]int // 勝つ入力列（辞書順、MaxPaths 本まで）
+	OptimalPath   []int   // 最終戦の結果値が最大の勝ち入力列（同値なら辞書順で最小）
+	OptimalResult float64 // OptimalPath の最終戦の結果値
+	States        int     // 転置表に登録した異なる状態数
+}
+
+// Winnable reports whether at least one input sequence wins.
+func (s Solution) Winnable() bool {
+	return s.WinningCount > 0
+}
+
+// Losable reports whether at least one input sequence loses.
+func (s Solution) Losable() bool {
+	return s.WinningCount < s.TotalPaths
+}
+
+// WinRate returns the exact win rate of uniformly random play.
+func (s Solution) WinRate() float64 {
+	if s.TotalPaths == 0 {
+		return 0
+	}
+	return float64(s.WinningCount) / float64(s.TotalPaths)
+}
+
+// Solve: seed のルールで全入力列を網羅的に調べ、勝ち入力列の数・一覧・最適解を返す
+// 同じターンで同じ（量子化した）player / enemy 状態に至った部分木は転置表で共有する。player / enemy は変更しない
+// 勝ち入力列の数には全ての末端が要るため枝刈りはしない。最適解だけが要るなら分枝限定の SolveOptimal を使う
+func Solve(seed int64, battleMax int, player *domain.Player, enemy *domain.Enemy, opts SolveOptions) (Solution, error) {
+	if battleMax <= 0 || player == nil || enemy == nil {
+		panic("Invalid parameters: battleMax must be > 0, player and enemy must not be nil")
+	}
+	if battleMax > solveMaxBattles {
+		return Solution{}, ErrSolveTooLarge
+	}
+	s := &solver{
+		battleMax: battleMax,
+		rule:      NewRuleForSeed(seed, matrixSize(player)),
+		memo:      map[string]*solveNode{},
+		maxStates: 1 << 20,
+		quantum:   1e-9,
+	}
+	maxPaths := solveDefaultMaxPaths
+	if opts.MaxPaths > 0 {
+		maxPaths = opts.MaxPaths
+	}
+	s.apply(opts)
+	p, e := player.Clone(), enemy.Clone()
+	p.Reset()
+	e.Reset()
+	root, err := s.solve(0, p, e)
+	if err != nil {
+		return Solution{}, err
+	}
+
+	sol := Solution{
+		Seed:         seed,
+		TotalPaths:   int64(math.Pow10(battleMax)),
+		WinningCount: root.wins,
+		States:       len(s.memo),
+	}
+	if root.wins > 0 {
+		sol.OptimalResult = root.best
+		for n := root; n != nil; n = n.next[n.bestIn] {
+			sol.OptimalPath = append(sol.OptimalPath, n.bestIn)
+		}
+	}
+	s.collect(root, nil, maxPaths, &sol.WinningPaths)
+	return sol, nil
+}
+
+// OptimalSolution is the answer of SolveOptimal.
+type OptimalSolution struct {
+	Seed   int64
+	Path   []int   // 最終戦の結果値が最大の勝ち入力列（同値なら辞書順で最小）。勝てなければ nil
+	Result float64 // Path の最終戦の結果値
+	States int     // 転置表に登録した異なる状態数
+	Pruned int     // 上界で打ち切った部分木の数
+}
+
+// Found reports whether a winning input sequence exists.
+func (s OptimalSolution) Found() bool {
+	return s.Path != nil
+}
+
+// SolveOptimal: Solve の OptimalPath / OptimalResult と同じ最適解を、勝ち入力列を数えずに分枝限定で求める
+// 最終戦の結果は正規化した行列の平均なので、プレイヤー側はルール行列の行和から、敵側は正規化した敵行列の和から抑えられる。
+// 最終ターンの直前では敵行列が確定しているためこの上界は締まり、それ以前は敵行列の和の下限 -n を使う。
+// 上界が暫定解（勝ちの条件から初期値 0）以下の部分木は刈る。player / enemy は変更しない
+func SolveOptimal(seed int64, battleMax int, player *domain.Player, enemy *domain.Enemy, opts SolveOptions) (OptimalSolution, error) {
+	if battleMax <= 0 || player == nil || enemy == nil {
+		panic("Invalid parameters: battleMax must be > 0, player and enemy must not be nil")
+	}
+	if battleMax > solveMaxBattles {
+		return OptimalSolution{}, ErrSolveTooLarge
+	}
+	s := &solver{
+		battleMax: battleMax,
+		rule:      NewRuleForSeed(seed, matrixSize(player)),
+		optMemo:   map[string]*optimalNode{},
+		maxStates: 1 << 20,
+		quantum:   1e-9,
+	}
+	s.apply(opts)
+	s.rowBound = ruleRowBound(s.rule.Matrix)
+	p, e := player.Clone(), enemy.Clone()
+	p.Reset()
+	e.Reset()
+	s.empty = p.MatrixState == nil || p.MatrixState.Rows == 0
+	root, err := s.optimal(0, p, e, 0)
+	if err != nil {
+		return OptimalSolution{}, err
+	}
+
+	sol := OptimalSolution{Seed: seed, States: len(s.optMemo), Pruned: s.pruned}
+	if root.exact {
+		sol.Result = root.best
+		for n := root; n != nil; n = n.next[n.bestIn] {
+			sol.Path = append(sol.Path, n.bestIn)
+		}
+	}
+	return sol, nil
+}
+
+// solveNode: あるターン・状態から先の部分木の要約（転置表のエントリ）
+type solveNode struct {
+	wins       int64          // 勝ちの末端数
+	best       float64        // 勝ちの末端での最終戦結果の最大値
+	bestIn     int            // best に至る入力
+	next       [10]*solveNode // 入力ごとの子（最終ターンでは nil）
+	leafWin    [10]bool       // 最終ターンでの入力ごとの勝敗
+	leafResult [10]float64
+}
+
+// optimalNode: SolveOptimal の転置表のエントリ
+// exact なら best / bestIn は部分木の真の最大値とその入力。そうでなければ部分木の最大値は bound 以下
+type optimalNode struct {
+	exact  bool
+	best   float64
+	bestIn int
+	bound  float64
+	next   [10]*optimalNode
+}
+
+type solver struct {
+	battleMax int
+	rule      *domain.RuleMatrix
+	memo      map[string]*solveNode
+	optMemo   map[string]*optimalNode
+	maxStates int
+	quantum   float64
+	buf       []byte
+	rowBound  float64 // 正規化したプレイヤー行列とルール行列の積の和の上界
+	empty     bool    // プレイヤー行列が空（結果は常に 0）
+	pruned    int
+}
+
+// apply: opts の上限・刻みを反映する（0 以下は既定値のまま）
+func (s *solver) apply(opts SolveOptions) {
+	if opts.MaxStates > 0 {
+		s.maxStates = opts.MaxStates
+	}
+	if opts.Quantum > 0 {
+		s.quantum = opts.Quantum
+	}
+}
+
+// solve: turn 手目の直前の状態から先を評価する
+func (s *solver) solve(turn int, player *domain.Player, enemy *domain.Enemy) (*solveNode, error) {
+	key := s.stateKey(turn, player, enemy)
+	if n, ok := s.memo[key]; ok {
+		return n, nil
+	}
+	if len(s.memo) >= s.maxStates {
+		return nil, ErrSolveTooLarge
+	}
+	n := &solveNode{best: math.Inf(-1), bestIn: -1}
+	for input := 0; input < 10; input++ {
+		p, e := player.Clone(), enemy.Clone()
+		result, win := NewBattleService(p, e, s.rule).DoBattleTurn(float64(input)/9, turn)
+		if turn == s.battleMax-1 {
+			n.leafWin[input], n.leafResult[input] = win, result
+			if win {
+				n.wins++
+				if result > n.best {
+					n.best, n.bestIn = result, input
+				}
+			}
+			continue
+		}
+		child, err := s.solve(turn+1, p, e)
+		if err != nil {
+			return nil, err
+		}
+		n.next[input] = child
+		n.wins += child.wins
+		if child.wins > 0 && child.best > n.best {
+			n.best, n.bestIn = child.best, input
+		}
+	}
+	s.memo[key] = n
+	return n, nil
+}
+
+// optimal: turn 手目の直前の状態から先で、floor を超える最終戦結果の最大値を求める
+// floor は DFS の順に見つかった暫定解で、単調に増える。そのため floor 以下と分かった部分木は後で見直さなくてよい
+func (s *solver) optimal(turn int, player *domain.Player, enemy *domain.Enemy, floor float64) (*optimalNode, error) {
+	key := s.stateKey(turn, player, enemy)
+	if n, ok := s.optMemo[key]; ok {
+		return n, nil
+	}
+	if len(s.optMemo) >= s.maxStates {
+		return nil, ErrSolveTooLarge
+	}
+	n := &optimalNode{best: math.Inf(-1), bestIn: -1, bound: floor}
+	s.optMemo[key] = n
+	if s.resultBound(turn, enemy)+solveBoundSlack <= floor {
+		s.pruned++
+		return n, nil
+	}
+	cur := floor
+	for input := 0; input < 10; input++ {
+		p, e := player.Clone(), enemy.Clone()
+		result, _ := NewBattleService(p, e, s.rule).DoBattleTurn(float64(input)/9, turn)
+		if turn == s.battleMax-1 {
+			// 勝ちは結果が正のときなので、floor（0 以上）を超えた末端は勝ち
+			if result > cur {
+				cur, n.best, n.bestIn = result, result, input
+			}
+			continue
+		}
+		child, err := s.optimal(turn+1, p, e, cur)
+		if err != nil {
+			return nil, err
+		}
+		n.next[input] = child
+		if child.exact && child.best > cur {
+			cur, n.best, n.bestIn = child.best, child.best, input
+		}
+	}
+	n.exact = n.bestIn >= 0
+	return n, nil
+}
+
+// resultBound: turn 手目の直前の状態から到達できる最終戦結果の上界
+// 結果は (Σ(P·R) - ΣE) / n² で、P・E は正規化される。ΣE は最終ターンの直前なら確定し、それ以前は -n 以上
+func (s *solver) resultBound(turn int, enemy *domain.Enemy) float64 {
+	if s.empty {
+		return 0
+	}
+	n := float64(s.rule.Matrix.Rows)
+	enemySum := -n
+	if turn == s.battleMax-1 {
+		enemySum = normalizedSum(enemy.MatrixState)
+	}
+	return (s.rowBound - enemySum) / (n * n)
+}
+
+// ruleRowBound: 単位ノルムの P について Σ(P·R) = Σ_ik P_ik·r_k（r_k は R の行和）の上界 √(n·Σr_k²)
+func ruleRowBound(rule *domain.Matrix) float64 {
+	sum := 0.0
+	for _, row := range rule.Data {
+		r := 0.0
+		for _, v := range row {
+			r += v
+		}
+		sum += r * r
+	}
+	return math.Sqrt(float64(rule.Rows) * sum)
+}
+
+// normalizedSum: L2 ノルムが 1 になるよう正規化した行列の要素和（零行列なら 0）
+func normalizedSum(m *domain.Matrix) float64 {
+	if m == nil {
+		return 0
+	}
+	sum, squares := 0.0, 0.0
+	for _, row := range m.Data {
+		for _, v := range row {
+			sum += v
+			squares += v * v
+		}
+	}
+	if squares == 0 {
+		return 0
+	}
+	return sum / math.Sqrt(squares)
+}
+
+// stateKey: ターンと量子化した行列の値を連結した転置表のキー
+// プレイヤーの成長率はターンから決まるため含めない
+func (s *solver) stateKey(turn int, player *domain.Player, enemy *domain.Enemy) string {
+	b := binary.AppendVarint(s.buf[:0], int64(turn))
+	for _, m := range []*domain.Matrix{player.MatrixState, enemy.MatrixState} {
+		if m == nil {
+			b = binary.AppendVarint(b, -1)
+			continue
+		}
+		b = binary.AppendVarint(b, int64(m.Rows))
+		for _, row := range m.Data {
+			for _, v := range row {
+				b = binary.AppendVarint(b, int64(math.Round(v/s.quantum)))
+			}
+		}
+	}
+	s.buf = b
+	return string(b)
+}
+
+// collect: 勝ちの末端を持つ部分木だけを辿り、勝ち入力列を辞書順に集める（limit 本まで）
+// 部分木の勝ち数は solve で確定済みのため、勝ち数 0 の部分木を飛ばしても結果は変わらない
+func (s *solver) collect(n *solveNode, prefix []int, limit int, out *[][]int) {
+	if n.wins == 0 {
+		return
+	}
+	for input := 0; input < 10; input++ {
+		if limit > 0 && len(*out) >= limit {
+			return
+		}
+		path := append(prefix[:len(prefix):len(prefix)], input)
+		if n.next[input] == nil {
+			if n.leafWin[input] {
+				*out = append(*out, path)
+			}
+			continue
+		}
+		s.collect(n.next[input], path, limit, out)
+	}
+}
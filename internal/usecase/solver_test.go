@@ -0,0 +1,333 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"axiom_shift/internal/domain"
+	"axiom_shift/internal/logic"
+)
+
+// bruteForce: 全入力列を Reset から再生して勝ち入力列と最大の最終戦結果を求める
+func bruteForce(t *testing.T, seed int64, battleMax int, player *domain.Player, enemy *domain.Enemy) ([][]int, float64) {
+	t.Helper()
+	rule := NewRuleForSeed(seed, matrixSize(player))
+	var wins [][]int
+	best := math.Inf(-1)
+	path := make([]int, battleMax)
+	var rec func(turn int)
+	rec = func(turn int) {
+		if turn == battleMax {
+			player.Reset()
+			enemy.Reset()
+			service := NewBattleService(player, enemy, rule)
+			var result float64
+			var win bool
+			for b, in := range path {
+				result, win = service.DoBattleTurn(float64(in)/9, b)
+			}
+			if win {
+				wins = append(wins, append([]int(nil), path...))
+				best = math.Max(best, result)
+			}
+			return
+		}
+		for in := 0; in < 10; in++ {
+			path[turn] = in
+			rec(turn + 1)
+		}
+	}
+	rec(0)
+	return wins, best
+}
+
+func TestSolve_MatchesBruteForce(t *testing.T) {
+	tests := []struct {
+		name      string
+		seed      int64
+		battleMax int
+		player    [][]float64
+		enemy     [][]float64
+	}{
+		{"2x2 one battle", 13, 1, [][]float64{{2, 0}, {0, 2}}, [][]float64{{0, 2}, {2, 0}}},
+		{"2x2 three battles", 13, 3, [][]float64{{2, 0}, {0, 2}}, [][]float64{{0, 2}, {2, 0}}},
+		{"3x3 two battles", FallbackSeed, 2, [][]float64{{2, 0, 0}, {0, 2, 0}, {0, 0, 2}}, [][]float64{{0, 0, 2}, {0, 2, 0}, {2, 0, 0}}},
+		{"3x3 three battles", FallbackSeed, 3, [][]float64{{2, 0, 0}, {0, 2, 0}, {0, 0, 2}}, [][]float64{{0, 0, 2}, {0, 2, 0}, {2, 0, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := domain.NewPlayer(domain.NewMatrix(tt.player), 0.5)
+			enemy := domain.NewEnemy("E", domain.NewMatrix(tt.enemy), 0.5)
+			sol, err := Solve(tt.seed, tt.battleMax, player, enemy, SolveOptions{})
+			if err != nil {
+				t.Fatalf("Solve error: %v", err)
+			}
+			wins, best := bruteForce(t, tt.seed, tt.battleMax, player, enemy)
+			if sol.WinningCount != int64(len(wins)) || !reflect.DeepEqual(sol.WinningPaths, wins) {
+				t.Errorf("winning paths = %d %v, want %d %v", sol.WinningCount, sol.WinningPaths, len(wins), wins)
+			}
+			if sol.TotalPaths != int64(math.Pow10(tt.battleMax)) {
+				t.Errorf("TotalPaths = %d", sol.TotalPaths)
+			}
+			if sol.Winnable() && (math.Abs(sol.OptimalResult-best) > 1e-12 || len(sol.OptimalPath) != tt.battleMax) {
+				t.Errorf("optimal = %v %v, want result %v", sol.OptimalPath, sol.OptimalResult, best)
+			}
+			if !sol.Winnable() && sol.OptimalPath != nil {
+				t.Errorf("OptimalPath = %v, want nil", sol.OptimalPath)
+			}
+		})
+	}
+}
+
+func TestSolve_OracleForFindValidSeed(t *testing.T) {
+	tests := []struct {
+		name       string
+		battleMax  int
+		masterSeed int64
+	}{
+		{"4 battles", 4, 1},
+		{"5 battles", 5, 2},
+		{"6 battles", 6, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := newProofPair()
+			r, err := FindValidSeed(context.Background(), tt.battleMax, player, enemy, logic.NewSeedManagerWithFixedValue(tt.masterSeed), SeedSearchOptions{})
+			if err != nil {
+				t.Fatalf("FindValidSeed error: %v", err)
+			}
+			sol, err := Solve(r.Seed, tt.battleMax, player, enemy, SolveOptions{MaxPaths: 1 << 20})
+			if err != nil {
+				t.Fatalf("Solve error: %v", err)
+			}
+			// 受理された seed は双方に勝ち筋がある
+			if !sol.Winnable() || !sol.Losable() {
+				t.Fatalf("accepted seed %d has %d/%d winning paths", r.Seed, sol.WinningCount, sol.TotalPaths)
+			}
+			winning := map[string]bool{}
+			for _, p := range sol.WinningPaths {
+				winning[pathKey(p)] = true
+			}
+			if !winning[pathKey(r.PlayerPath)] || winning[pathKey(r.EnemyPath)] {
+				t.Errorf("report paths disagree with solver: player %v, enemy %v", r.PlayerPath, r.EnemyPath)
+			}
+			// ランダムプレイの真の勝率はサンプリングの 99% 信頼区間に入る
+			if rate := sol.WinRate(); rate < r.Deep.Low || rate > r.Deep.High {
+				t.Errorf("exact win rate %v outside sampled interval [%v, %v]", rate, r.Deep.Low, r.Deep.High)
+			}
+		})
+	}
+}
+
+func TestSolve_Options(t *testing.T) {
+	tests := []struct {
+		name      string
+		battleMax int
+		opts      SolveOptions
+		wantErr   error
+		wantPaths int
+	}{
+		{"path limit", 3, SolveOptions{MaxPaths: 2}, nil, 2},
+		{"default path limit", 4, SolveOptions{}, nil, solveDefaultMaxPaths},
+		{"state limit", 4, SolveOptions{MaxStates: 10}, ErrSolveTooLarge, 0},
+		{"too many battles", solveMaxBattles + 1, SolveOptions{}, ErrSolveTooLarge, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := newProofPair()
+			sol, err := Solve(13, tt.battleMax, player, enemy, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Solve error = %v, want %v", err, tt.wantErr)
+			}
+			if len(sol.WinningPaths) != tt.wantPaths {
+				t.Errorf("len(WinningPaths) = %d, want %d", len(sol.WinningPaths), tt.wantPaths)
+			}
+			if err == nil && sol.WinningCount <= int64(tt.wantPaths) {
+				t.Errorf("WinningCount = %d should exceed the path limit", sol.WinningCount)
+			}
+		})
+	}
+}
+
+func TestSolve_Transpositions(t *testing.T) {
+	tests := []struct {
+		name       string
+		player     [][]float64
+		enemy      [][]float64
+		wantStates int
+	}{
+		// 空行列ではどの入力でも状態が変わらず、ターンごとに 1 状態
+		{"empty matrices", [][]float64{}, [][]float64{}, 5},
+		// 2x2 では 10 通りの入力が 4 マスに集約される: 1+4+16+64+256
+		{"2x2 inputs collapse", [][]float64{{2, 0}, {0, 2}}, [][]float64{{0, 2}, {2, 0}}, 341},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := domain.NewPlayer(domain.NewMatrix(tt.player), 0.5)
+			enemy := domain.NewEnemy("E", domain.NewMatrix(tt.enemy), 0.5)
+			sol, err := Solve(1, 5, player, enemy, SolveOptions{})
+			if err != nil {
+				t.Fatalf("Solve error: %v", err)
+			}
+			if sol.States != tt.wantStates {
+				t.Errorf("States = %d, want %d", sol.States, tt.wantStates)
+			}
+		})
+	}
+}
+
+func TestSolve_GuardCases(t *testing.T) {
+	player, enemy := newProofPair()
+	tests := []struct {
+		name      string
+		battleMax int
+		player    *domain.Player
+		enemy     *domain.Enemy
+		optimal   bool
+	}{
+		{"zero battleMax", 0, player, enemy, false},
+		{"nil player", 3, nil, enemy, false},
+		{"nil enemy", 3, player, nil, false},
+		{"optimal with zero battleMax", 0, player, enemy, true},
+		{"optimal with nil player", 3, nil, enemy, true},
+		{"optimal with nil enemy", 3, player, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic but did not panic")
+				}
+			}()
+			if tt.optimal {
+				_, _ = SolveOptimal(1, tt.battleMax, tt.player, tt.enemy, SolveOptions{})
+				return
+			}
+			_, _ = Solve(1, tt.battleMax, tt.player, tt.enemy, SolveOptions{})
+		})
+	}
+}
+
+func TestSolveOptimal_MatchesSolve(t *testing.T) {
+	pair2 := [2][][]float64{{{2, 0}, {0, 2}}, {{0, 2}, {2, 0}}}
+	pair3 := [2][][]float64{{{2, 0, 0}, {0, 2, 0}, {0, 0, 2}}, {{0, 0, 2}, {0, 2, 0}, {2, 0, 0}}}
+	tests := []struct {
+		name       string
+		seed       int64
+		battleMax  int
+		matrices   [2][][]float64
+		wantFound  bool
+		wantPruned bool // 上界で刈れる部分木がある
+	}{
+		{"2x2 winnable", 4, 5, pair2, true, true},
+		{"2x2 unwinnable", 5, 5, pair2, false, true},
+		{"2x2 one battle", 4, 1, pair2, true, false},
+		{"2x2 no wins without pruning", 1, 4, pair2, false, false},
+		{"3x3 winnable", 3, 3, pair3, true, false},
+		{"3x3 other seed", 4, 3, pair3, true, false},
+		{"empty matrices", 1, 3, [2][][]float64{{}, {}}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := domain.NewPlayer(domain.NewMatrix(tt.matrices[0]), 0.5)
+			enemy := domain.NewEnemy("E", domain.NewMatrix(tt.matrices[1]), 0.5)
+			want, err := Solve(tt.seed, tt.battleMax, player, enemy, SolveOptions{})
+			if err != nil {
+				t.Fatalf("Solve error: %v", err)
+			}
+			got, err := SolveOptimal(tt.seed, tt.battleMax, player, enemy, SolveOptions{})
+			if err != nil {
+				t.Fatalf("SolveOptimal error: %v", err)
+			}
+			if got.Found() != tt.wantFound || want.Winnable() != tt.wantFound {
+				t.Fatalf("Found = %v, Winnable = %v, want %v", got.Found(), want.Winnable(), tt.wantFound)
+			}
+			// 刈っても Solve の全探索と同じ最適解になる
+			if !reflect.DeepEqual(got.Path, want.OptimalPath) || math.Abs(got.Result-want.OptimalResult) > 1e-12 {
+				t.Errorf("optimal = %v %v, want %v %v", got.Path, got.Result, want.OptimalPath, want.OptimalResult)
+			}
+			if (got.Pruned > 0) != tt.wantPruned {
+				t.Errorf("Pruned = %d, want pruning %v", got.Pruned, tt.wantPruned)
+			}
+			if got.Seed != tt.seed || got.States == 0 || got.States > want.States {
+				t.Errorf("Seed %d, States %d (Solve %d)", got.Seed, got.States, want.States)
+			}
+		})
+	}
+}
+
+func TestSolveOptimal_Options(t *testing.T) {
+	tests := []struct {
+		name      string
+		battleMax int
+		opts      SolveOptions
+		wantErr   error
+	}{
+		{"defaults", 3, SolveOptions{}, nil},
+		{"coarse quantum", 3, SolveOptions{Quantum: 1e-3}, nil},
+		{"state limit", 4, SolveOptions{MaxStates: 10}, ErrSolveTooLarge},
+		{"too many battles", solveMaxBattles + 1, SolveOptions{}, ErrSolveTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, enemy := newProofPair()
+			sol, err := SolveOptimal(13, tt.battleMax, player, enemy, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SolveOptimal error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil && sol.Found() {
+				t.Errorf("Path = %v on error", sol.Path)
+			}
+			if err == nil && len(sol.Path) != tt.battleMax {
+				t.Errorf("Path = %v, want %d inputs", sol.Path, tt.battleMax)
+			}
+		})
+	}
+}
+
+func TestNormalizedSum(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *domain.Matrix
+		want float64
+	}{
+		{"nil", nil, 0},
+		{"zero", domain.NewMatrix([][]float64{{0, 0}, {0, 0}}), 0},
+		{"unit", domain.NewMatrix([][]float64{{1, 0}, {0, 0}}), 1},
+		{"uniform", domain.NewMatrix([][]float64{{2, 2}, {2, 2}}), 2},
+		{"mixed signs", domain.NewMatrix([][]float64{{3, -4}}), -0.2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizedSum(tt.m); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("normalizedSum = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolution_Rates(t *testing.T) {
+	tests := []struct {
+		name     string
+		sol      Solution
+		wantWin  bool
+		wantLose bool
+		wantRate float64
+	}{
+		{"zero value", Solution{}, false, false, 0},
+		{"all lose", Solution{TotalPaths: 10}, false, true, 0},
+		{"mixed", Solution{TotalPaths: 10, WinningCount: 3}, true, true, 0.3},
+		{"all win", Solution{TotalPaths: 10, WinningCount: 10}, true, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.sol.Winnable() != tt.wantWin || tt.sol.Losable() != tt.wantLose || tt.sol.WinRate() != tt.wantRate {
+				t.Errorf("Winnable/Losable/WinRate = %v/%v/%v, want %v/%v/%v",
+					tt.sol.Winnable(), tt.sol.Losable(), tt.sol.WinRate(), tt.wantWin, tt.wantLose, tt.wantRate)
+			}
+		})
+	}
+}
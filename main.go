@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
 
+	"axiom_shift/internal/cli"
 	"axiom_shift/internal/game"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		code := cli.Run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+		stop()
+		os.Exit(code)
+	}
 	g := game.NewGame()
 	ebiten.SetWindowSize(640, 480)
 	ebiten.SetWindowTitle("Axiom Shift")